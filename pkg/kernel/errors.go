@@ -0,0 +1,113 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package kernel
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// LinkNotFoundError is returned when no link matches the given name and/or PCI address
+type LinkNotFoundError struct {
+	Name       string
+	PCIAddress string
+	Err        error
+}
+
+func (e *LinkNotFoundError) Error() string {
+	msg := fmt.Sprintf("link not found: name=%s", e.Name)
+	if e.PCIAddress != "" {
+		msg += fmt.Sprintf(" or pciAddress=%s", e.PCIAddress)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *LinkNotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// NetNSGoneError is returned when the net NS is closed or doesn't exist anymore
+type NetNSGoneError struct {
+	NetNS string
+	Err   error
+}
+
+func (e *NetNSGoneError) Error() string {
+	return fmt.Sprintf("net NS %s is gone: %v", e.NetNS, e.Err)
+}
+
+func (e *NetNSGoneError) Unwrap() error {
+	return e.Err
+}
+
+// NameCollisionError is returned when a link with the name already exists in the net NS
+type NameCollisionError struct {
+	Name  string
+	NetNS string
+	Err   error
+}
+
+func (e *NameCollisionError) Error() string {
+	return fmt.Sprintf("link %s already exists in net NS %s: %v", e.Name, e.NetNS, e.Err)
+}
+
+func (e *NameCollisionError) Unwrap() error {
+	return e.Err
+}
+
+// PermissionError is returned when the operation on the link is not permitted
+type PermissionError struct {
+	Name string
+	Err  error
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("operation on link %s not permitted: %v", e.Name, e.Err)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return e.Err
+}
+
+// ToLinkError converts the netlink error of an operation on the name link in the netNS net NS to one of
+// the typed errors above. The error is returned as it is if it has no typed counterpart.
+func ToLinkError(err error, name, netNS string) error {
+	if err == nil {
+		return nil
+	}
+	var notFound netlink.LinkNotFoundError
+	switch {
+	case errors.As(err, &notFound), errors.Is(err, unix.ENODEV):
+		return &LinkNotFoundError{Name: name, Err: err}
+	case errors.Is(err, unix.EBADF):
+		return &NetNSGoneError{NetNS: netNS, Err: err}
+	case errors.Is(err, unix.EEXIST), errors.Is(err, unix.ENOTUNIQ):
+		return &NameCollisionError{Name: name, NetNS: netNS, Err: err}
+	case errors.Is(err, unix.EPERM), errors.Is(err, unix.EACCES):
+		return &PermissionError{Name: name, Err: err}
+	}
+	return err
+}
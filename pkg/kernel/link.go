@@ -73,13 +73,13 @@ func (l *link) MoveToNetns(target netns.NsHandle) error {
 	// set link down
 	err := l.SetAdminState(DOWN)
 	if err != nil {
-		return errors.Errorf("failed to move link %s to netns: %q", l.link, err)
+		return errors.Wrapf(err, "failed to move link %s to netns", l.link)
 	}
 
 	// set netns
 	err = netlink.LinkSetNsFd(l.link, int(target))
 	if err != nil {
-		return errors.Errorf("failed to move link %s to netns: %q", l.link, err)
+		return errors.Wrapf(ToLinkError(err, l.GetName(), target.String()), "failed to move link %s to netns", l.link)
 	}
 
 	l.netns = target
@@ -137,12 +137,12 @@ func (l *link) SetAdminState(state State) error {
 	case DOWN:
 		err := netlink.LinkSetDown(l.link)
 		if err != nil {
-			return errors.Errorf("failed to set %s down: %s", l.link, err)
+			return errors.Wrapf(ToLinkError(err, l.GetName(), l.netns.String()), "failed to set %s down", l.link)
 		}
 	case UP:
 		err := netlink.LinkSetUp(l.link)
 		if err != nil {
-			return errors.Errorf("failed to bring %s up: %s", l.link, err)
+			return errors.Wrapf(ToLinkError(err, l.GetName(), l.netns.String()), "failed to bring %s up", l.link)
 		}
 	}
 
@@ -153,7 +153,7 @@ func (l *link) SetName(name string) error {
 	if l.link.Attrs().Name != name {
 		err := netlink.LinkSetName(l.link, name)
 		if err != nil {
-			return errors.Errorf("failed to set interface name to %s: %s", name, err)
+			return errors.Wrapf(ToLinkError(err, name, l.netns.String()), "failed to set interface name to %s", name)
 		}
 	}
 
@@ -178,10 +178,14 @@ func FindHostDevice(pciAddress, name string, namespaces ...netns.NsHandle) (Link
 	}
 
 	// search for link with a matching name or PCI address in the provided namespaces
+	var netNSGone *NetNSGoneError
 	for _, ns := range namespaces {
 		for _, search := range attempts {
 			found, err := search(ns, name, pciAddress)
 			if err != nil {
+				if netNSGone == nil {
+					errors.As(err, &netNSGone)
+				}
 				continue
 			}
 
@@ -193,7 +197,11 @@ func FindHostDevice(pciAddress, name string, namespaces ...netns.NsHandle) (Link
 			}
 		}
 	}
-	return nil, errors.Errorf("failed to obtain netlink link matching criteria: name=%s or pciAddress=%s", name, pciAddress)
+	// the link is also reported as not found if a net NS is gone, the cause can be told by errors.As
+	if netNSGone != nil {
+		return nil, &LinkNotFoundError{Name: name, PCIAddress: pciAddress, Err: netNSGone}
+	}
+	return nil, &LinkNotFoundError{Name: name, PCIAddress: pciAddress}
 }
 
 func searchByPCIAddress(ns netns.NsHandle, _, pciAddress string) (netlink.Link, error) {
@@ -269,13 +277,13 @@ func searchByName(ns netns.NsHandle, name, _ string) (netlink.Link, error) {
 	// execute in context of the pod's namespace
 	handle, err := netlink.NewHandleAt(ns)
 	if err != nil {
-		return nil, errors.Errorf("failed to create netlink handler: %s", err)
+		return nil, errors.Wrap(ToLinkError(err, name, ns.String()), "failed to create netlink handler")
 	}
 
 	// get link
 	link, err := handle.LinkByName(name)
 	if err != nil {
-		return nil, errors.Errorf("failed to get link with name %s", name)
+		return nil, errors.Wrapf(ToLinkError(err, name, ns.String()), "failed to get link with name %s", name)
 	}
 
 	return link, nil
@@ -283,17 +291,21 @@ func searchByName(ns netns.NsHandle, name, _ string) (netlink.Link, error) {
 
 // GetNetlinkHandle - mechanism to netlink.Handle for the NetNS specified in mechanism
 func GetNetlinkHandle(urlString string) (*netlink.Handle, error) {
-	curNSHandle, err := nshandle.Current()
+	nsHandle, err := nshandle.FromURL(urlString)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = curNSHandle.Close() }()
+	defer func() { _ = nsHandle.Close() }()
 
-	nsHandle, err := nshandle.FromURL(urlString)
+	return newNetlinkHandleAt(nsHandle)
+}
+
+func newNetlinkHandleAt(nsHandle netns.NsHandle) (*netlink.Handle, error) {
+	curNSHandle, err := nshandle.Current()
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = nsHandle.Close() }()
+	defer func() { _ = curNSHandle.Close() }()
 
 	handle, err := netlink.NewHandleAtFrom(nsHandle, curNSHandle)
 	if err != nil {
@@ -0,0 +1,203 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package kernel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// DefaultNetlinkHandleIdleTimeout is the time an unused netlink handle is kept in the NetlinkHandleCache
+const DefaultNetlinkHandleIdleTimeout = time.Minute
+
+type netlinkHandleCacheKey struct{}
+
+type cachedNetlinkHandle struct {
+	handle    *netlink.Handle
+	inode     uint64
+	refs      int
+	idleTimer *time.Timer
+}
+
+// NetlinkHandleCache keeps the netlink handles of the net NSes keyed by the net NS inode, so a handle is not
+// created for every Request. The net NS URLs are mapped to the inodes, so a cached handle is found by only
+// checking the URL still refers to the same net NS. A handle keeps its net NS alive, so a handle is closed
+// when it is not used for the idle timeout.
+type NetlinkHandleCache struct {
+	mu          sync.Mutex
+	handles     map[uint64]*cachedNetlinkHandle
+	urls        map[string]uint64
+	idleTimeout time.Duration
+}
+
+// NewNetlinkHandleCache returns a new NetlinkHandleCache, DefaultNetlinkHandleIdleTimeout is used if idleTimeout is 0
+func NewNetlinkHandleCache(idleTimeout time.Duration) *NetlinkHandleCache {
+	if idleTimeout == 0 {
+		idleTimeout = DefaultNetlinkHandleIdleTimeout
+	}
+	return &NetlinkHandleCache{
+		handles:     make(map[uint64]*cachedNetlinkHandle),
+		urls:        make(map[string]uint64),
+		idleTimeout: idleTimeout,
+	}
+}
+
+// Get returns the netlink handle for the net NS specified by the URL and the function releasing it.
+// The handle must not be closed by the caller.
+func (c *NetlinkHandleCache) Get(urlString string) (*netlink.Handle, func(), error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.lookup(urlString)
+	if !ok {
+		var err error
+		if entry, err = c.open(urlString); err != nil {
+			return nil, nil, err
+		}
+	}
+	entry.refs++
+	if entry.idleTimer != nil {
+		entry.idleTimer.Stop()
+		entry.idleTimer = nil
+	}
+
+	var once sync.Once
+	return entry.handle, func() {
+		once.Do(func() { c.release(entry) })
+	}, nil
+}
+
+// Close closes all the handles which are not in use
+func (c *NetlinkHandleCache) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, entry := range c.handles {
+		if entry.refs == 0 {
+			c.remove(entry)
+		}
+	}
+}
+
+// lookup returns the cached handle of the URL, the net NS is only stat-ed to check the URL still refers to it
+func (c *NetlinkHandleCache) lookup(urlString string) (*cachedNetlinkHandle, bool) {
+	inode, ok := c.urls[urlString]
+	if !ok {
+		return nil, false
+	}
+	if _, statInode, err := nshandle.StatURL(urlString); err != nil || statInode != inode {
+		delete(c.urls, urlString)
+		return nil, false
+	}
+	entry, ok := c.handles[inode]
+	return entry, ok
+}
+
+// open opens the net NS of the URL and returns its cached handle, the handle is created if there is none
+func (c *NetlinkHandleCache) open(urlString string) (*cachedNetlinkHandle, error) {
+	nsHandle, err := nshandle.FromURL(urlString)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = nsHandle.Close() }()
+
+	inode, err := nshandle.Inode(nsHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := c.handles[inode]
+	if !ok {
+		handle, err := newNetlinkHandleAt(nsHandle)
+		if err != nil {
+			return nil, err
+		}
+		entry = &cachedNetlinkHandle{handle: handle, inode: inode}
+		c.handles[inode] = entry
+	}
+	c.urls[urlString] = inode
+	return entry, nil
+}
+
+// release starts the idle timer of the handle when it is not used anymore
+func (c *NetlinkHandleCache) release(entry *cachedNetlinkHandle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.refs--
+	if entry.refs > 0 {
+		return
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(c.idleTimeout, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		// the handle may have been used again and released meanwhile, then it has a newer timer
+		if entry.idleTimer == timer && entry.refs == 0 && c.handles[entry.inode] == entry {
+			c.remove(entry)
+		}
+	})
+	entry.idleTimer = timer
+}
+
+func (c *NetlinkHandleCache) remove(entry *cachedNetlinkHandle) {
+	if entry.idleTimer != nil {
+		entry.idleTimer.Stop()
+		entry.idleTimer = nil
+	}
+	entry.handle.Close()
+	delete(c.handles, entry.inode)
+	for urlString, inode := range c.urls {
+		if inode == entry.inode {
+			delete(c.urls, urlString)
+		}
+	}
+}
+
+// WithNetlinkHandleCache returns a context with the NetlinkHandleCache used by AcquireNetlinkHandle
+func WithNetlinkHandleCache(ctx context.Context, cache *NetlinkHandleCache) context.Context {
+	return context.WithValue(ctx, netlinkHandleCacheKey{}, cache)
+}
+
+// NetlinkHandleCacheFromContext returns the NetlinkHandleCache of the context, or nil if there is none
+func NetlinkHandleCacheFromContext(ctx context.Context) *NetlinkHandleCache {
+	cache, _ := ctx.Value(netlinkHandleCacheKey{}).(*NetlinkHandleCache)
+	return cache
+}
+
+// AcquireNetlinkHandle - returns netlink.Handle for the NetNS specified in mechanism and the function releasing it.
+// The handle is taken from the NetlinkHandleCache of the context if there is any, otherwise a new handle is created
+// and closed on release.
+func AcquireNetlinkHandle(ctx context.Context, urlString string) (*netlink.Handle, func(), error) {
+	if cache := NetlinkHandleCacheFromContext(ctx); cache != nil {
+		return cache.Get(urlString)
+	}
+	handle, err := GetNetlinkHandle(urlString)
+	if err != nil {
+		return nil, nil, err
+	}
+	return handle, handle.Close, nil
+}
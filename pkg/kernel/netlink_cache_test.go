@@ -0,0 +1,124 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func cachedHandles(c *NetlinkHandleCache) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.handles)
+}
+
+func TestNetlinkHandleCache_Refs(t *testing.T) {
+	cache := NewNetlinkHandleCache(time.Hour)
+	defer cache.Close()
+
+	// the URLs refer to the same net NS, so they share the handle
+	pidURL := fmt.Sprintf("pid://%d", os.Getpid())
+	fileURL := fmt.Sprintf("file:///proc/%d/ns/net", os.Getpid())
+
+	handle, release, err := cache.Get(pidURL)
+	require.NoError(t, err)
+	otherHandle, otherRelease, err := cache.Get(fileURL)
+	require.NoError(t, err)
+	require.Same(t, handle, otherHandle)
+	require.Equal(t, 1, cachedHandles(cache))
+
+	// the handle is in use, so it is not closed
+	release()
+	release()
+	cache.Close()
+	require.Equal(t, 1, cachedHandles(cache))
+
+	otherRelease()
+	cache.Close()
+	require.Equal(t, 0, cachedHandles(cache))
+
+	_, _, err = cache.Get("nsm://unknown")
+	require.Error(t, err)
+	require.Equal(t, 0, cachedHandles(cache))
+}
+
+func TestNetlinkHandleCache_IdleTimeout(t *testing.T) {
+	const idleTimeout = 50 * time.Millisecond
+
+	cache := NewNetlinkHandleCache(idleTimeout)
+	defer cache.Close()
+
+	urlString := fmt.Sprintf("pid://%d", os.Getpid())
+
+	handle, release, err := cache.Get(urlString)
+	require.NoError(t, err)
+	release()
+
+	// the handle is used again before the idle timeout, so the timer is stopped
+	reused, releaseReused, err := cache.Get(urlString)
+	require.NoError(t, err)
+	require.Same(t, handle, reused)
+	time.Sleep(2 * idleTimeout)
+	require.Equal(t, 1, cachedHandles(cache))
+
+	releaseReused()
+	require.Eventually(t, func() bool { return cachedHandles(cache) == 0 }, time.Second, idleTimeout/5)
+
+	// a new handle is created once the idle one is closed
+	_, release, err = cache.Get(urlString)
+	require.NoError(t, err)
+	defer release()
+	require.Equal(t, 1, cachedHandles(cache))
+}
+
+func TestNetlinkHandleCache_DefaultIdleTimeout(t *testing.T) {
+	require.Equal(t, DefaultNetlinkHandleIdleTimeout, NewNetlinkHandleCache(0).idleTimeout)
+}
+
+func TestAcquireNetlinkHandle(t *testing.T) {
+	urlString := fmt.Sprintf("pid://%d", os.Getpid())
+
+	// without a cache the handle is closed on release
+	handle, release, err := AcquireNetlinkHandle(context.Background(), urlString)
+	require.NoError(t, err)
+	require.NotNil(t, handle)
+	release()
+
+	cache := NewNetlinkHandleCache(time.Hour)
+	defer cache.Close()
+
+	ctx := WithNetlinkHandleCache(context.Background(), cache)
+	require.Same(t, cache, NetlinkHandleCacheFromContext(ctx))
+
+	handle, release, err = AcquireNetlinkHandle(ctx, urlString)
+	require.NoError(t, err)
+	cached, releaseCached, err := cache.Get(urlString)
+	require.NoError(t, err)
+	require.Same(t, handle, cached)
+	release()
+	releaseCached()
+}
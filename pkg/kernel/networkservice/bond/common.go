@@ -0,0 +1,217 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package bond
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/metadata"
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+
+	kernellink "github.com/ljkiraly/sdk-kernel/pkg/kernel"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/inject"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+type createdKey struct{}
+
+func isCreated(ctx context.Context, isClient bool) bool {
+	_, ok := metadata.Map(ctx, isClient).Load(createdKey{})
+	return ok
+}
+
+func create(ctx context.Context, conn *networkservice.Connection, o *options, isClient bool) (err error) {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil || isCreated(ctx, isClient) {
+		return nil
+	}
+	members, ok := vfconfig.LoadMembers(ctx, isClient)
+	if !ok || len(members) == 0 {
+		return nil
+	}
+	logger := log.FromContext(ctx).WithField("bond", "create")
+
+	hostNetNS, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = hostNetNS.Close() }()
+
+	contNetNS, err := nshandle.FromURL(mech.GetNetNSURL())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = contNetNS.Close() }()
+
+	ifName := mech.GetInterfaceName()
+	defer func() {
+		if err != nil {
+			_ = cleanup(ctx, conn.GetId(), members, ifName, o, hostNetNS, contNetNS, logger)
+		}
+	}()
+
+	// The bond is created right in the target netNS with the requested name, so it never
+	// appears in the forwarder netNS and its name can't collide with the forwarder interfaces.
+	attrs := netlink.NewLinkAttrs()
+	attrs.Name = ifName
+	attrs.Namespace = netlink.NsFd(contNetNS)
+	bond := netlink.NewLinkBond(attrs)
+	bond.Mode = o.mode
+	bond.Miimon = int(o.miimon.Milliseconds())
+	if err = netlink.LinkAdd(bond); err != nil {
+		return errors.Wrapf(err, "failed to create bond %s (%s)", ifName, o.mode)
+	}
+
+	handle, err := netlink.NewHandleAt(contNetNS)
+	if err != nil {
+		return errors.Wrap(err, "failed to create netlink contNetNS handle")
+	}
+	defer handle.Close()
+
+	bondLink, err := handle.LinkByName(ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find link %s", ifName)
+	}
+
+	for i, member := range members {
+		if err = enslave(ctx, handle, bondLink, member, conn.GetId(), getMemberName(ifName, i), o, hostNetNS, contNetNS); err != nil {
+			return err
+		}
+	}
+
+	if err = handle.LinkSetUp(bondLink); err != nil {
+		return errors.Wrapf(err, "failed to setup link for the interface %v", bondLink)
+	}
+	metadata.Map(ctx, isClient).Store(createdKey{}, struct{}{})
+	logger.Debugf("Bond %s (%s) created with %d VFs in netNS %v", ifName, o.mode, len(members), contNetNS)
+	return nil
+}
+
+// enslave moves the member VF to the contNetNS with the memberName name the same way inject does and adds it to the bond
+func enslave(ctx context.Context, handle *netlink.Handle, bondLink netlink.Link, member *vfconfig.VFConfig, connID, memberName string,
+	o *options, hostNetNS, contNetNS netns.NsHandle) error {
+	name, err := o.mover.MoveToContNetNS(ctx, member, connID, memberName, hostNetNS, contNetNS)
+	if err != nil {
+		return err
+	}
+	l, err := handle.LinkByName(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find link %s", name)
+	}
+	// a link must be down to be enslaved
+	if err = handle.LinkSetDown(l); err != nil {
+		return errors.Wrapf(err, "failed to down link %s", name)
+	}
+	if err = handle.LinkSetMasterByIndex(l, bondLink.Attrs().Index); err != nil {
+		return errors.Wrapf(err, "failed to enslave %s to bond %s", name, bondLink.Attrs().Name)
+	}
+	if err = handle.LinkSetUp(l); err != nil {
+		return errors.Wrapf(err, "failed to up link %s", name)
+	}
+	return nil
+}
+
+func del(ctx context.Context, conn *networkservice.Connection, o *options, isClient bool) error {
+	if _, ok := metadata.Map(ctx, isClient).LoadAndDelete(createdKey{}); !ok {
+		return nil
+	}
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil
+	}
+	members, _ := vfconfig.LoadMembers(ctx, isClient)
+	logger := log.FromContext(ctx).WithField("bond", "del")
+
+	hostNetNS, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = hostNetNS.Close() }()
+
+	contNetNS, err := nshandle.FromURL(mech.GetNetNSURL())
+	if err != nil {
+		// the bond is removed together with the target netNS and the VFs are returned to the init netNS, they are
+		// still renamed back and their references are dropped
+		logger.Warnf("Can not open target netNS, might be deleted already (%v)", err)
+		contNetNS = netns.None()
+	}
+	defer func() {
+		if contNetNS.IsOpen() {
+			_ = contNetNS.Close()
+		}
+	}()
+
+	return cleanup(ctx, conn.GetId(), members, mech.GetInterfaceName(), o, hostNetNS, contNetNS, logger)
+}
+
+// cleanup deletes the bond and moves the member VFs back to the forwarder netNS with their original names,
+// it goes on if a step fails and returns the first error
+func cleanup(ctx context.Context, connID string, members []*vfconfig.VFConfig, ifName string, o *options,
+	hostNetNS, contNetNS netns.NsHandle, logger log.Logger) error {
+	var errs []error
+	if contNetNS.IsOpen() {
+		if l, _ := kernellink.FindHostDevice("", ifName, contNetNS); l != nil {
+			if _, ok := l.GetLink().(*netlink.Bond); ok {
+				errs = append(errs, inject.DeleteInterface(ifName, contNetNS, logger))
+			}
+		}
+	}
+	for i, member := range members {
+		// the member may have got an alternative name on the collision
+		memberName := getMemberName(ifName, i)
+		if contNetNS.IsOpen() {
+			if l, _ := kernellink.FindHostDevice(member.VFPCIAddress, memberName, contNetNS); l != nil {
+				memberName = l.GetName()
+			}
+		}
+		err := o.mover.MoveToHostNetNS(ctx, member, connID, memberName, hostNetNS, contNetNS)
+		var linkNotFound *inject.LinkNotFoundError
+		var netNSGone *inject.NetNSGoneError
+		if errors.As(err, &linkNotFound) || errors.As(err, &netNSGone) {
+			logger.Warnf("Can not find bond member %s, might be deleted already (%v)", memberName, err)
+			continue
+		}
+		errs = append(errs, err)
+	}
+	for _, err := range errs {
+		if err != nil {
+			logger.Warnf("Failed to clean up bond %s (%v)", ifName, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// getMemberName returns the name of the i-th bond member VF in the target netNS
+func getMemberName(ifName string, i int) string {
+	suffix := fmt.Sprintf("-%d", i)
+	if len(ifName)+len(suffix) > kernel.LinuxIfMaxLength {
+		ifName = ifName[:kernel.LinuxIfMaxLength-len(suffix)]
+	}
+	return ifName + suffix
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package bond
+
+import (
+	"time"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/inject"
+)
+
+const defaultMIIMon = 100 * time.Millisecond
+
+type options struct {
+	mode          netlink.BondMode
+	miimon        time.Duration
+	injectOptions []inject.Option
+	mover         *inject.Mover
+}
+
+// Option is an option pattern for NewClient, NewServer
+type Option func(o *options)
+
+// WithMode - sets the bond mode, netlink.BOND_MODE_ACTIVE_BACKUP by default. netlink.BOND_MODE_802_3AD is
+// supported as well.
+func WithMode(mode netlink.BondMode) Option {
+	return func(o *options) {
+		o.mode = mode
+	}
+}
+
+// WithMIIMon - sets the link monitoring interval of the bond, 100ms by default
+func WithMIIMon(interval time.Duration) Option {
+	return func(o *options) {
+		o.miimon = interval
+	}
+}
+
+// WithInjectOptions - sets the options the member VFs are moved with, the same as for inject.NewServer or
+// inject.NewClient. The references are shared with the inject chain elements using the same state file.
+func WithInjectOptions(opts ...inject.Option) Option {
+	return func(o *options) {
+		o.injectOptions = append(o.injectOptions, opts...)
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		mode:   netlink.BOND_MODE_ACTIVE_BACKUP,
+		miimon: defaultMIIMon,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.mover = inject.NewMover(o.injectOptions...)
+	return o
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package bond
+
+import (
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/inject"
+)
+
+func TestOptions(t *testing.T) {
+	o := newOptions()
+	require.Equal(t, netlink.BOND_MODE_ACTIVE_BACKUP, o.mode)
+	require.Equal(t, defaultMIIMon, o.miimon)
+	require.Empty(t, o.injectOptions)
+	require.NotNil(t, o.mover)
+
+	o = newOptions(
+		WithMode(netlink.BOND_MODE_802_3AD),
+		WithMIIMon(time.Second),
+		WithInjectOptions(inject.WithCollisionPolicy(inject.CollisionPolicyAlternative)),
+		WithInjectOptions(inject.WithPropertyPreservation()),
+	)
+	require.Equal(t, netlink.BOND_MODE_802_3AD, o.mode)
+	require.Equal(t, time.Second, o.miimon)
+	require.Len(t, o.injectOptions, 2)
+	require.NotNil(t, o.mover)
+}
+
+func TestGetMemberName(t *testing.T) {
+	require.Equal(t, "nsm-0", getMemberName("nsm", 0))
+	require.Equal(t, "nsm-12", getMemberName("nsm", 12))
+
+	// the name is truncated to fit the interface name length
+	name := getMemberName("nsm-long-ifname", 1)
+	require.Len(t, name, kernel.LinuxIfMaxLength)
+	require.Equal(t, "nsm-long-ifna-1", name)
+}
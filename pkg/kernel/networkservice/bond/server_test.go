@@ -0,0 +1,197 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build perm
+// +build perm
+
+package bond_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/core/chain"
+	"github.com/ljkiraly/sdk/pkg/networkservice/core/next"
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/bond"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/inject"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+)
+
+const ifName = "nsm-bond"
+
+var memberNames = []string{"nsm-bond-vf0", "nsm-bond-vf1"}
+
+func TestBondServer_CreateDeletePerm(t *testing.T) {
+	addMembers(t)
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		&membersServer{},
+		bond.NewServer(bond.WithInjectOptions(inject.WithStateFile(filepath.Join(t.TempDir(), "state")))),
+	)
+
+	request := newRequest(target)
+	conn, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+
+	l, err := handle.LinkByName(ifName)
+	require.NoError(t, err)
+	require.IsType(t, &netlink.Bond{}, l)
+	require.Equal(t, netlink.BOND_MODE_ACTIVE_BACKUP, l.(*netlink.Bond).Mode)
+	require.NotZero(t, l.Attrs().Flags&net.FlagUp)
+
+	// the members are moved to the target netNS and enslaved
+	for i, name := range memberNames {
+		_, err = netlink.LinkByName(name)
+		require.Error(t, err)
+
+		member, err := handle.LinkByName(fmt.Sprintf("%s-%d", ifName, i))
+		require.NoError(t, err)
+		require.Equal(t, l.Attrs().Index, member.Attrs().MasterIndex)
+	}
+
+	// a refresh keeps the bond
+	request.Connection = conn
+	conn, err = server.Request(context.Background(), request)
+	require.NoError(t, err)
+	_, err = handle.LinkByName(ifName)
+	require.NoError(t, err)
+
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+
+	// the bond is deleted and the members are moved back with their original names
+	_, err = handle.LinkByName(ifName)
+	require.Error(t, err)
+	for _, name := range memberNames {
+		_, err = netlink.LinkByName(name)
+		require.NoError(t, err)
+	}
+}
+
+func TestBondServer_RequestFailedPerm(t *testing.T) {
+	addMembers(t)
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		&membersServer{},
+		bond.NewServer(bond.WithInjectOptions(inject.WithStateFile(filepath.Join(t.TempDir(), "state")))),
+		injecterror.NewServer(),
+	)
+
+	_, err = server.Request(context.Background(), newRequest(target))
+	require.Error(t, err)
+
+	// the bond is deleted and the members are moved back if the Request fails
+	_, err = handle.LinkByName(ifName)
+	require.Error(t, err)
+	for _, name := range memberNames {
+		_, err = netlink.LinkByName(name)
+		require.NoError(t, err)
+	}
+}
+
+// membersServer stores the members of the bond the way a VF selecting chain element does
+type membersServer struct{}
+
+func (s *membersServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	var members []*vfconfig.VFConfig
+	for _, name := range memberNames {
+		members = append(members, &vfconfig.VFConfig{VFInterfaceName: name})
+	}
+	if _, ok := vfconfig.LoadMembers(ctx, metadata.IsClient(s)); !ok {
+		vfconfig.StoreMembers(ctx, metadata.IsClient(s), members)
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *membersServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func newRequest(target netns.NsHandle) *networkservice.NetworkServiceRequest {
+	mechanism := kernel.New(fmt.Sprintf("fd://%d", int(target)))
+	kernel.ToMechanism(mechanism).SetInterfaceName(ifName)
+
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:        "nsm-conn",
+			Mechanism: mechanism,
+		},
+	}
+}
+
+// addMembers adds the links standing for the VFs to the forwarder netNS, they are deleted on the test cleanup
+func addMembers(t *testing.T) {
+	for _, name := range memberNames {
+		require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+			LinkAttrs: netlink.LinkAttrs{Name: name},
+			PeerName:  name + "-p",
+		}))
+	}
+	t.Cleanup(func() {
+		for _, name := range memberNames {
+			if l, err := netlink.LinkByName(name + "-p"); err == nil {
+				_ = netlink.LinkDel(l)
+			}
+		}
+	})
+}
+
+func newNSHandle(t *testing.T) netns.NsHandle {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	baseHandle, err := netns.Get()
+	require.NoError(t, err)
+	defer func() {
+		_ = netns.Set(baseHandle)
+		_ = baseHandle.Close()
+	}()
+
+	newHandle, err := netns.New()
+	require.NoError(t, err)
+
+	return newHandle
+}
@@ -38,26 +38,24 @@ import (
 
 	link "github.com/ljkiraly/sdk-kernel/pkg/kernel"
 	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/vlanlink"
 )
 
 func create(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
-	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && mechanism.GetVLAN() == 0 {
+	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && vlanlink.IsConfigurable(ctx, isClient, mechanism) {
 		// Note: These are switched from normal because if we are the client, we need to assign the IP
 		// in the Endpoints NetNS for the Dst.  If we are the *server* we need to assign the IP for the
 		// clients NetNS (ie the source).
-		ipNets := conn.GetContext().GetIpContext().GetSrcIPNets()
-		if isClient {
-			ipNets = conn.GetContext().GetIpContext().GetDstIPNets()
-		}
+		ipNets := getIPNets(conn, isClient)
 		if ipNets == nil {
 			return nil
 		}
 
-		netlinkHandle, err := link.GetNetlinkHandle(mechanism.GetNetNSURL())
+		netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
 		if err != nil {
 			return err
 		}
-		defer netlinkHandle.Close()
+		defer releaseHandle()
 
 		ifName := mechanism.GetInterfaceName()
 		l, err := netlinkHandle.LinkByName(ifName)
@@ -69,64 +67,73 @@ func create(ctx context.Context, conn *networkservice.Connection, isClient bool)
 			return errors.Wrapf(err, "failed to setup link for the interface %v", l)
 		}
 
-		var forwarderNetNS netns.NsHandle
-		forwarderNetNS, err = nshandle.Current()
-		if err != nil {
-			return err
-		}
-		defer func() { _ = forwarderNetNS.Close() }()
-
-		var targetNetNS netns.NsHandle
-		targetNetNS, err = nshandle.FromURL(mechanism.GetNetNSURL())
+		targetNetNS, err := nshandle.FromURL(mechanism.GetNetNSURL())
 		if err != nil {
 			return err
 		}
 		defer func() { _ = targetNetNS.Close() }()
 
-		disableIPv6Filename := fmt.Sprintf("/proc/sys/net/ipv6/conf/%s/disable_ipv6", l.Attrs().Name)
-		if err = nshandle.RunIn(forwarderNetNS, targetNetNS, func() error {
-			return os.WriteFile(disableIPv6Filename, []byte("0"), 0o600)
-		}); err != nil {
-			return errors.Wrapf(err, "failed to set %s = 0", disableIPv6Filename)
-		}
+		return applyIPNets(ctx, targetNetNS, ipNets, netlinkHandle, l)
+	}
+	return nil
+}
 
-		ch := make(chan netlink.AddrUpdate)
-		done := make(chan struct{})
+func getIPNets(conn *networkservice.Connection, isClient bool) []*net.IPNet {
+	if isClient {
+		return conn.GetContext().GetIpContext().GetDstIPNets()
+	}
+	return conn.GetContext().GetIpContext().GetSrcIPNets()
+}
 
-		if err = netlink.AddrSubscribeWithOptions(ch, done, netlink.AddrSubscribeOptions{
-			Namespace:      &targetNetNS,
-			ReceiveTimeout: &unix.Timeval{Sec: 1},
-		}); err != nil {
-			return errors.Wrapf(err, "failed to subscribe for interface address updates")
-		}
+func applyIPNets(ctx context.Context, targetNetNS netns.NsHandle, ipNets []*net.IPNet, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	forwarderNetNS, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = forwarderNetNS.Close() }()
 
-		defer func() {
-			close(done)
-			// `ch` should be fully read after the `done` close to prevent goroutine leak in `netlink.AddrSubscribeWithOptions`
-			// nolint: revive
-			for range ch {
-			}
-		}()
+	disableIPv6Filename := fmt.Sprintf("/proc/sys/net/ipv6/conf/%s/disable_ipv6", l.Attrs().Name)
+	if err = nshandle.RunIn(forwarderNetNS, targetNetNS, func() error {
+		return os.WriteFile(disableIPv6Filename, []byte("0"), 0o600)
+	}); err != nil {
+		return errors.Wrapf(err, "failed to set %s = 0", disableIPv6Filename)
+	}
 
-		// Get IP addresses to add and to remove
-		toAdd, toRemove, err := getIPAddrDifferences(netlinkHandle, l, ipNets)
-		if err != nil {
-			return err
-		}
+	ch := make(chan netlink.AddrUpdate)
+	done := make(chan struct{})
 
-		// Remove no longer existing IPs
+	if err = netlink.AddrSubscribeWithOptions(ch, done, netlink.AddrSubscribeOptions{
+		Namespace:      &targetNetNS,
+		ReceiveTimeout: &unix.Timeval{Sec: 1},
+	}); err != nil {
+		return errors.Wrapf(err, "failed to subscribe for interface address updates")
+	}
 
-		if err := removeOldIPAddrs(ctx, netlinkHandle, l, toRemove); err != nil {
-			return err
+	defer func() {
+		close(done)
+		// `ch` should be fully read after the `done` close to prevent goroutine leak in `netlink.AddrSubscribeWithOptions`
+		// nolint: revive
+		for range ch {
 		}
+	}()
 
-		// Add new IP addresses
-		if err := addNewIPAddrs(ctx, netlinkHandle, l, toAdd); err != nil {
-			return err
-		}
-		return waitForIPNets(ctx, ch, l, toAdd)
+	// Get IP addresses to add and to remove
+	toAdd, toRemove, err := getIPAddrDifferences(netlinkHandle, l, ipNets)
+	if err != nil {
+		return err
 	}
-	return nil
+
+	// Remove no longer existing IPs
+
+	if err := removeOldIPAddrs(ctx, netlinkHandle, l, toRemove); err != nil {
+		return err
+	}
+
+	// Add new IP addresses
+	if err := addNewIPAddrs(ctx, netlinkHandle, l, toAdd); err != nil {
+		return err
+	}
+	return waitForIPNets(ctx, ch, l, toAdd)
 }
 
 func removeOldIPAddrs(ctx context.Context, netlinkHandle *netlink.Handle, l netlink.Link, ipAddrs []*net.IPNet) error {
@@ -234,3 +241,13 @@ func waitForIPNets(ctx context.Context, ch chan netlink.AddrUpdate, l netlink.Li
 		}
 	}
 }
+
+// Apply assigns the IP addresses of the connection context to the l kernel interface of the connection, the
+// interface and its targetNetNS net NS are resolved and the interface is set up by the caller
+func Apply(ctx context.Context, conn *networkservice.Connection, isClient bool, targetNetNS netns.NsHandle, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	ipNets := getIPNets(conn, isClient)
+	if ipNets == nil || kernel.ToMechanism(conn.GetMechanism()) == nil {
+		return nil
+	}
+	return applyIPNets(ctx, targetNetNS, ipNets, netlinkHandle, l)
+}
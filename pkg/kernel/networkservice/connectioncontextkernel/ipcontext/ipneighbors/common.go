@@ -38,17 +38,18 @@ import (
 
 	link "github.com/ljkiraly/sdk-kernel/pkg/kernel"
 	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/peer"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/vlanlink"
 
 	"github.com/ljkiraly/sdk/pkg/tools/log"
 )
 
 func create(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
-	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && mechanism.GetVLAN() == 0 {
-		netlinkHandle, err := link.GetNetlinkHandle(mechanism.GetNetNSURL())
+	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && vlanlink.IsConfigurable(ctx, isClient, mechanism) {
+		netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
 		if err != nil {
 			return err
 		}
-		defer netlinkHandle.Close()
+		defer releaseHandle()
 
 		ifName := mechanism.GetInterfaceName()
 		l, err := netlinkHandle.LinkByName(ifName)
@@ -56,31 +57,36 @@ func create(ctx context.Context, conn *networkservice.Connection, isClient bool)
 			return errors.Wrapf(err, "failed to find link %s", ifName)
 		}
 
-		if err := setIPContextNeighbors(ctx, netlinkHandle, conn.GetContext().GetIpContext().GetIpNeighbors(), l); err != nil {
-			return err
-		}
+		return applyNeighbors(ctx, conn, isClient, netlinkHandle, l)
+	}
+	return nil
+}
 
-		// If payload is IP - we need to add additional neighbor
-		if conn.GetPayload() == payload.IP {
-			peerLink, ok := peer.Load(ctx, isClient)
-			if !ok {
-				log.FromContext(ctx).Error("Peer link not found")
-				return nil
-			}
-			if peerLink == nil || peerLink.Attrs() == nil || peerLink.Attrs().HardwareAddr == nil {
-				panic(fmt.Sprintf("unable to construct peer ip neighbor %+v", peerLink))
-			}
+func applyNeighbors(ctx context.Context, conn *networkservice.Connection, isClient bool, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	if err := setIPContextNeighbors(ctx, netlinkHandle, conn.GetContext().GetIpContext().GetIpNeighbors(), l); err != nil {
+		return err
+	}
 
-			dstNets := conn.GetContext().GetIpContext().GetDstIPNets()
-			if isClient {
-				dstNets = conn.GetContext().GetIpContext().GetSrcIPNets()
-			}
+	// If payload is IP - we need to add additional neighbor
+	if conn.GetPayload() == payload.IP {
+		peerLink, ok := peer.Load(ctx, isClient)
+		if !ok {
+			log.FromContext(ctx).Error("Peer link not found")
+			return nil
+		}
+		if peerLink == nil || peerLink.Attrs() == nil || peerLink.Attrs().HardwareAddr == nil {
+			panic(fmt.Sprintf("unable to construct peer ip neighbor %+v", peerLink))
+		}
 
-			for _, dstNet := range dstNets {
-				if dstNet != nil {
-					if err := setPeerNeighbor(ctx, netlinkHandle, l, peerLink, dstNet); err != nil {
-						return err
-					}
+		dstNets := conn.GetContext().GetIpContext().GetDstIPNets()
+		if isClient {
+			dstNets = conn.GetContext().GetIpContext().GetSrcIPNets()
+		}
+
+		for _, dstNet := range dstNets {
+			if dstNet != nil {
+				if err := setPeerNeighbor(ctx, netlinkHandle, l, peerLink, dstNet); err != nil {
+					return err
 				}
 			}
 		}
@@ -150,3 +156,9 @@ func setPeerNeighbor(ctx context.Context, handle *netlink.Handle, l, peerLink ne
 		WithField("netlink", "NeighSet").Debug("setPeerNeighbor completed")
 	return nil
 }
+
+// Apply sets the IP neighbors of the connection context for the l kernel interface of the connection, the interface
+// is resolved by the caller
+func Apply(ctx context.Context, conn *networkservice.Connection, isClient bool, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	return applyNeighbors(ctx, conn, isClient, netlinkHandle, l)
+}
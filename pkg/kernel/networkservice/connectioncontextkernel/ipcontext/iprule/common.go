@@ -42,73 +42,81 @@ import (
 
 	link "github.com/ljkiraly/sdk-kernel/pkg/kernel"
 	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/vlanlink"
 )
 
 func create(ctx context.Context, conn *networkservice.Connection, tableIDs *genericsync.Map[string, policies], nsRTableNextIDToConnID *genericsync.Map[netnsRTableNextID, string]) error {
-	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && mechanism.GetVLAN() == 0 {
+	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && vlanlink.IsConfigurable(ctx, false, mechanism) {
 		// Construct the netlink handle for the target namespace for this kernel interface
-		netlinkHandle, err := link.GetNetlinkHandle(mechanism.GetNetNSURL())
+		netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
 		if err != nil {
 			return err
 		}
-		defer netlinkHandle.Close()
+		defer releaseHandle()
 
 		ifName := mechanism.GetInterfaceName()
 		l, err := netlinkHandle.LinkByName(ifName)
 		if err != nil {
 			return errors.Wrapf(err, "iprule: failed to create policy rules for interface %s", ifName)
 		}
-		connID := conn.GetId()
-		ps, ok := tableIDs.Load(connID)
-		if !ok {
-			if len(conn.Context.IpContext.Policies) == 0 {
-				return nil
-			}
-			ps = make(map[int]*networkservice.PolicyRoute)
-			tableIDs.Store(connID, ps)
-		}
+		return createPolicies(ctx, conn, mechanism, netlinkHandle, l, tableIDs, nsRTableNextIDToConnID)
+	}
+	return nil
+}
 
-		// Get netns for key to namespace to routing tableID map
-		netNS, err := nshandle.FromURL(mechanism.GetNetNSURL())
-		if err != nil {
-			return errors.Wrapf(err, "iprule: failed to create policy rules in namespace: %s", mechanism.GetNetNSURL())
+func createPolicies(ctx context.Context, conn *networkservice.Connection, mechanism *kernel.Mechanism, netlinkHandle *netlink.Handle, l netlink.Link,
+	tableIDs *genericsync.Map[string, policies], nsRTableNextIDToConnID *genericsync.Map[netnsRTableNextID, string]) error {
+	connID := conn.GetId()
+	ps, ok := tableIDs.Load(connID)
+	if !ok {
+		if len(conn.Context.IpContext.Policies) == 0 {
+			return nil
 		}
+		ps = make(map[int]*networkservice.PolicyRoute)
+		tableIDs.Store(connID, ps)
+	}
 
-		// Get policies to add and to remove
-		toAdd, toRemove := getPolicyDifferences(ps, conn.Context.IpContext.Policies)
+	// Get netns for key to namespace to routing tableID map
+	netNS, err := nshandle.FromURL(mechanism.GetNetNSURL())
+	if err != nil {
+		return errors.Wrapf(err, "iprule: failed to create policy rules in namespace: %s", mechanism.GetNetNSURL())
+	}
+	defer func() { _ = netNS.Close() }()
 
-		// Remove no longer existing policies
-		for tableID, policy := range toRemove {
-			if errRule := delRule(ctx, netlinkHandle, policy, tableID, l.Attrs().Index, createNetnsRTableNextID(netNS.UniqueId(), tableID), nsRTableNextIDToConnID); errRule != nil {
-				return errRule
-			}
-			delete(ps, tableID)
-			tableIDs.Store(connID, ps)
+	// Get policies to add and to remove
+	toAdd, toRemove := getPolicyDifferences(ps, conn.Context.IpContext.Policies)
+
+	// Remove no longer existing policies
+	for tableID, policy := range toRemove {
+		if errRule := delRule(ctx, netlinkHandle, policy, tableID, l.Attrs().Index, createNetnsRTableNextID(netNS.UniqueId(), tableID), nsRTableNextIDToConnID); errRule != nil {
+			return errRule
 		}
+		delete(ps, tableID)
+		tableIDs.Store(connID, ps)
+	}
 
-		// Add new policies
-		for _, policy := range toAdd {
-			var tableID int
-			// get a free table ID until we succeed
-			for {
-				tableID, err = getFreeTableID(ctx, netlinkHandle)
-				if err != nil {
-					return err
-				}
-				nsrtid := createNetnsRTableNextID(netNS.UniqueId(), tableID)
-				storedConnID, _ := nsRTableNextIDToConnID.LoadOrStore(nsrtid, connID)
-				log.FromContext(ctx).
-					WithField("nsrtid", nsrtid).
-					WithField("ConnID", storedConnID).
-					Debug("iprule:createNetnsRTableNextID")
-				if connID == storedConnID {
-					// No other connection adding policy using this free routing table ID
-					break
-				}
-			}
-			if err := addPolicy(ctx, netlinkHandle, policy, l, ps, tableIDs, tableID, connID); err != nil {
+	// Add new policies
+	for _, policy := range toAdd {
+		var tableID int
+		// get a free table ID until we succeed
+		for {
+			tableID, err = getFreeTableID(ctx, netlinkHandle)
+			if err != nil {
 				return err
 			}
+			nsrtid := createNetnsRTableNextID(netNS.UniqueId(), tableID)
+			storedConnID, _ := nsRTableNextIDToConnID.LoadOrStore(nsrtid, connID)
+			log.FromContext(ctx).
+				WithField("nsrtid", nsrtid).
+				WithField("ConnID", storedConnID).
+				Debug("iprule:createNetnsRTableNextID")
+			if connID == storedConnID {
+				// No other connection adding policy using this free routing table ID
+				break
+			}
+		}
+		if err := addPolicy(ctx, netlinkHandle, policy, l, ps, tableIDs, tableID, connID); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -276,12 +284,12 @@ func routeAdd(ctx context.Context, handle *netlink.Handle, l netlink.Link, route
 }
 
 func del(ctx context.Context, conn *networkservice.Connection, tableIDs *genericsync.Map[string, policies], nsRTableNextIDToConnID *genericsync.Map[netnsRTableNextID, string]) error {
-	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && mechanism.GetVLAN() == 0 {
-		netlinkHandle, err := link.GetNetlinkHandle(mechanism.GetNetNSURL())
+	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && vlanlink.IsConfigurable(ctx, false, mechanism) {
+		netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
 		if err != nil {
 			return err
 		}
-		defer netlinkHandle.Close()
+		defer releaseHandle()
 		ifName := mechanism.GetInterfaceName()
 		l, err := netlinkHandle.LinkByName(ifName)
 		if err != nil {
@@ -293,6 +301,7 @@ func del(ctx context.Context, conn *networkservice.Connection, tableIDs *generic
 			if err != nil {
 				return errors.Wrapf(err, "iprule: failed to delete policy rules in namespace: %s", mechanism.GetNetNSURL())
 			}
+			defer func() { _ = netNS.Close() }()
 			for tableID, policy := range ps {
 				if err := delRule(ctx, netlinkHandle, policy, tableID, l.Attrs().Index, createNetnsRTableNextID(netNS.UniqueId(), tableID), nsRTableNextIDToConnID); err != nil {
 					return err
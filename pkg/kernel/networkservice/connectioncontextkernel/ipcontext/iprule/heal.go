@@ -35,10 +35,11 @@ import (
 
 	link "github.com/ljkiraly/sdk-kernel/pkg/kernel"
 	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/vlanlink"
 )
 
 func recoverTableIDs(ctx context.Context, conn *networkservice.Connection, tableIDs *genericsync.Map[string, policies], nsRTableNextIDToConnID *genericsync.Map[netnsRTableNextID, string]) error {
-	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && mechanism.GetVLAN() == 0 {
+	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && vlanlink.IsConfigurable(ctx, false, mechanism) {
 		_, ok := tableIDs.Load(conn.GetId())
 		if ok {
 			return nil
@@ -48,11 +49,11 @@ func recoverTableIDs(ctx context.Context, conn *networkservice.Connection, table
 			return nil
 		}
 
-		netlinkHandle, err := link.GetNetlinkHandle(mechanism.GetNetNSURL())
+		netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
 		if err != nil {
 			return err
 		}
-		defer netlinkHandle.Close()
+		defer releaseHandle()
 
 		ifName := mechanism.GetInterfaceName()
 		l, err := netlinkHandle.LinkByName(ifName)
@@ -60,35 +61,40 @@ func recoverTableIDs(ctx context.Context, conn *networkservice.Connection, table
 			return errors.Wrapf(err, "iprule: failed to recover table IDs for interface: %s", ifName)
 		}
 
-		podRules, err := netlinkHandle.RuleList(netlink.FAMILY_ALL)
+		return recoverPolicies(ctx, conn, mechanism, netlinkHandle, l, nsRTableNextIDToConnID)
+	}
+	return nil
+}
+
+func recoverPolicies(ctx context.Context, conn *networkservice.Connection, mechanism *kernel.Mechanism, netlinkHandle *netlink.Handle, l netlink.Link,
+	nsRTableNextIDToConnID *genericsync.Map[netnsRTableNextID, string]) error {
+	podRules, err := netlinkHandle.RuleList(netlink.FAMILY_ALL)
+	if err != nil {
+		return errors.Wrapf(err, "iprule: failed to recover table IDs in namespace: %s", mechanism.GetNetNSURL())
+	}
+
+	tableIDtoPolicyMap := make(map[int]*networkservice.PolicyRoute)
+	// try to find the corresponding missing policies in the network namespace of the pod
+	for _, policy := range conn.Context.IpContext.Policies {
+		policyRule, err := policyToRule(policy)
 		if err != nil {
-			return errors.Wrapf(err, "iprule: failed to recover table IDs in namespace: %s", mechanism.GetNetNSURL())
+			return err
 		}
-
-		tableIDtoPolicyMap := make(map[int]*networkservice.PolicyRoute)
-		// try to find the corresponding missing policies in the network namespace of the pod
-		for _, policy := range conn.Context.IpContext.Policies {
-			policyRule, err := policyToRule(policy)
-			if err != nil {
-				return err
-			}
-			for i := range podRules {
-				if ruleEquals(&podRules[i], policyRule) {
-					tableIDtoPolicyMap[podRules[i].Table] = policy
-					log.FromContext(ctx).
-						WithField("From", policy.From).
-						WithField("IPProto", policy.Proto).
-						WithField("DstPort", policy.DstPort).
-						WithField("SrcPort", policy.SrcPort).
-						WithField("Table", podRules[i].Table).Debug("policy recovered")
-					break
-				}
+		for i := range podRules {
+			if ruleEquals(&podRules[i], policyRule) {
+				tableIDtoPolicyMap[podRules[i].Table] = policy
+				log.FromContext(ctx).
+					WithField("From", policy.From).
+					WithField("IPProto", policy.Proto).
+					WithField("DstPort", policy.DstPort).
+					WithField("SrcPort", policy.SrcPort).
+					WithField("Table", podRules[i].Table).Debug("policy recovered")
+				break
 			}
 		}
-
-		return deleteRemainders(ctx, netlinkHandle, tableIDtoPolicyMap, podRules, l, mechanism.GetNetNSURL(), nsRTableNextIDToConnID)
 	}
-	return nil
+
+	return deleteRemainders(ctx, netlinkHandle, tableIDtoPolicyMap, podRules, l, mechanism.GetNetNSURL(), nsRTableNextIDToConnID)
 }
 
 func deleteRemainders(ctx context.Context, netlinkHandle *netlink.Handle, tableIDtoPolicyMap map[int]*networkservice.PolicyRoute, podRules []netlink.Rule, l netlink.Link, mechanismNetNSURL string, nsRTableNextIDToConnID *genericsync.Map[netnsRTableNextID, string]) error {
@@ -97,6 +103,7 @@ func deleteRemainders(ctx context.Context, netlinkHandle *netlink.Handle, tableI
 	if err != nil {
 		return err
 	}
+	defer func() { _ = netNS.Close() }()
 	for tableID, policy := range tableIDtoPolicyMap {
 		usage := 0
 		for i := range podRules {
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package iprule
+
+import (
+	"context"
+
+	"github.com/edwarnicke/genericsync"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/vishvananda/netlink"
+)
+
+// Rules keeps the policy routing tables of the connections, it applies the policies of the connection context
+// out of the NewServer chain element
+type Rules struct {
+	tables                 *genericsync.Map[string, policies]
+	nsRTableNextIDToConnID *genericsync.Map[netnsRTableNextID, string]
+}
+
+// NewRules creates a new Rules
+func NewRules() *Rules {
+	return &Rules{
+		tables:                 new(genericsync.Map[string, policies]),
+		nsRTableNextIDToConnID: new(genericsync.Map[netnsRTableNextID, string]),
+	}
+}
+
+// Apply sets the policy rules and routing tables of the connection context for the l kernel interface of the
+// connection, the interface is resolved by the caller
+func (r *Rules) Apply(ctx context.Context, conn *networkservice.Connection, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	mechanism := kernel.ToMechanism(conn.GetMechanism())
+	if mechanism == nil {
+		return nil
+	}
+	if _, ok := r.tables.Load(conn.GetId()); !ok && len(conn.GetContext().GetIpContext().GetPolicies()) > 0 {
+		if err := recoverPolicies(ctx, conn, mechanism, netlinkHandle, l, r.nsRTableNextIDToConnID); err != nil {
+			return err
+		}
+	}
+	return createPolicies(ctx, conn, mechanism, netlinkHandle, l, r.tables, r.nsRTableNextIDToConnID)
+}
+
+// Delete deletes the policy rules and routing tables of the connection
+func (r *Rules) Delete(ctx context.Context, conn *networkservice.Connection) error {
+	return del(ctx, conn, r.tables, r.nsRTableNextIDToConnID)
+}
@@ -33,15 +33,16 @@ import (
 	"github.com/vishvananda/netlink"
 
 	link "github.com/ljkiraly/sdk-kernel/pkg/kernel"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/vlanlink"
 )
 
 func create(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
-	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && mechanism.GetVLAN() == 0 {
-		netlinkHandle, err := link.GetNetlinkHandle(mechanism.GetNetNSURL())
+	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && vlanlink.IsConfigurable(ctx, isClient, mechanism) {
+		netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
 		if err != nil {
 			return err
 		}
-		defer netlinkHandle.Close()
+		defer releaseHandle()
 
 		ifName := mechanism.GetInterfaceName()
 		l, err := netlinkHandle.LinkByName(ifName)
@@ -53,24 +54,29 @@ func create(ctx context.Context, conn *networkservice.Connection, isClient bool)
 			return errors.Wrapf(err, "failed to setup link for the interface %v", l)
 		}
 
-		var linkRoutes []*networkservice.Route
-		var routes []*networkservice.Route
-		if isClient {
-			linkRoutes = conn.GetContext().GetIpContext().GetSrcIPRoutes()
-			routes = conn.GetContext().GetIpContext().GetDstRoutesWithExplicitNextHop()
-		} else {
-			linkRoutes = conn.GetContext().GetIpContext().GetDstIPRoutes()
-			routes = conn.GetContext().GetIpContext().GetSrcRoutesWithExplicitNextHop()
-		}
-		for _, route := range linkRoutes {
-			if err := routeAdd(ctx, netlinkHandle, l, netlink.SCOPE_LINK, route); err != nil {
-				return err
-			}
+		return applyRoutes(ctx, conn, isClient, netlinkHandle, l)
+	}
+	return nil
+}
+
+func applyRoutes(ctx context.Context, conn *networkservice.Connection, isClient bool, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	var linkRoutes []*networkservice.Route
+	var routes []*networkservice.Route
+	if isClient {
+		linkRoutes = conn.GetContext().GetIpContext().GetSrcIPRoutes()
+		routes = conn.GetContext().GetIpContext().GetDstRoutesWithExplicitNextHop()
+	} else {
+		linkRoutes = conn.GetContext().GetIpContext().GetDstIPRoutes()
+		routes = conn.GetContext().GetIpContext().GetSrcRoutesWithExplicitNextHop()
+	}
+	for _, route := range linkRoutes {
+		if err := routeAdd(ctx, netlinkHandle, l, netlink.SCOPE_LINK, route); err != nil {
+			return err
 		}
-		for _, route := range routes {
-			if err := routeAdd(ctx, netlinkHandle, l, netlink.SCOPE_UNIVERSE, route); err != nil {
-				return err
-			}
+	}
+	for _, route := range routes {
+		if err := routeAdd(ctx, netlinkHandle, l, netlink.SCOPE_UNIVERSE, route); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -117,3 +123,9 @@ func routeAdd(ctx context.Context, handle *netlink.Handle, l netlink.Link, scope
 		WithField("netlink", "RouteReplace").Debug("completed")
 	return nil
 }
+
+// Apply adds the routes of the connection context for the l kernel interface of the connection, the interface is
+// resolved and set up by the caller
+func Apply(ctx context.Context, conn *networkservice.Connection, isClient bool, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	return applyRoutes(ctx, conn, isClient, netlinkHandle, l)
+}
@@ -29,12 +29,14 @@ import (
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
 	"github.com/ljkiraly/sdk/pkg/tools/log"
 	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
 
 	link "github.com/ljkiraly/sdk-kernel/pkg/kernel"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/vlanlink"
 )
 
-func setMTU(ctx context.Context, conn *networkservice.Connection) error {
-	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && mechanism.GetVLAN() == 0 {
+func setMTU(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && vlanlink.IsConfigurable(ctx, isClient, mechanism) {
 		// Note: These are switched from normal because if we are the client, we need to assign the IP
 		// in the Endpoints NetNS for the Dst.  If we are the *server* we need to assign the IP for the
 		// clients NetNS (ie the source).
@@ -43,11 +45,11 @@ func setMTU(ctx context.Context, conn *networkservice.Connection) error {
 			return nil
 		}
 
-		netlinkHandle, err := link.GetNetlinkHandle(mechanism.GetNetNSURL())
+		netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
 		if err != nil {
 			return err
 		}
-		defer netlinkHandle.Close()
+		defer releaseHandle()
 
 		ifName := mechanism.GetInterfaceName()
 		l, err := netlinkHandle.LinkByName(ifName)
@@ -59,15 +61,30 @@ func setMTU(ctx context.Context, conn *networkservice.Connection) error {
 			return errors.Wrapf(err, "failed to setup link for the interface %v", l)
 		}
 
-		now := time.Now()
-		if err := netlinkHandle.LinkSetMTU(l, int(mtu)); err != nil {
-			return errors.Wrapf(err, "error attempting to set MTU on link %q to value %q", l.Attrs().Name, mtu)
-		}
-		log.FromContext(ctx).
-			WithField("link.Name", l.Attrs().Name).
-			WithField("MTU", mtu).
-			WithField("duration", time.Since(now)).
-			WithField("netlink", "LinkSetMTU").Debug("completed")
+		return applyMTU(ctx, mtu, netlinkHandle, l)
+	}
+	return nil
+}
+
+func applyMTU(ctx context.Context, mtu uint32, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	now := time.Now()
+	if err := netlinkHandle.LinkSetMTU(l, int(mtu)); err != nil {
+		return errors.Wrapf(err, "error attempting to set MTU on link %q to value %q", l.Attrs().Name, mtu)
 	}
+	log.FromContext(ctx).
+		WithField("link.Name", l.Attrs().Name).
+		WithField("MTU", mtu).
+		WithField("duration", time.Since(now)).
+		WithField("netlink", "LinkSetMTU").Debug("completed")
 	return nil
 }
+
+// Apply sets the MTU of the connection context on the l kernel interface of the connection, the interface is
+// resolved and set up by the caller
+func Apply(ctx context.Context, conn *networkservice.Connection, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	mtu := conn.GetContext().GetMTU()
+	if mtu == 0 {
+		return nil
+	}
+	return applyMTU(ctx, mtu, netlinkHandle, l)
+}
@@ -75,7 +75,7 @@ func (m *mtuServer) Request(ctx context.Context, request *networkservice.Network
 		return nil, err
 	}
 
-	if err := setMTU(ctx, conn); err != nil {
+	if err := setMTU(ctx, conn, false); err != nil {
 		logger.Debugf("about to Close due to error: %s", err.Error())
 
 		closeCtx, cancelClose := postponeCtxFunc()
@@ -27,6 +27,8 @@ import (
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
 	"github.com/ljkiraly/sdk/pkg/networkservice/core/next"
 	"github.com/ljkiraly/sdk/pkg/tools/postpone"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/vlanlink"
 )
 
 type pinggrouprangeClient struct{}
@@ -42,7 +44,7 @@ func (p *pinggrouprangeClient) Request(ctx context.Context, request *networkserv
 	if err != nil {
 		return nil, err
 	}
-	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && mechanism.GetVLAN() == 0 {
+	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && vlanlink.IsConfigurable(ctx, true, mechanism) {
 		if err := applyPingGroupRange(ctx, mechanism); err != nil {
 			closeCtx, cancelClose := postponeCtxFunc()
 			defer cancelClose()
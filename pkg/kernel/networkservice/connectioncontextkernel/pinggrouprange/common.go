@@ -23,11 +23,13 @@ import (
 	"github.com/pkg/errors"
 	"github.com/vishvananda/netns"
 
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
 
 	"github.com/ljkiraly/sdk/pkg/tools/log"
 
 	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/vlanlink"
 )
 
 // See https://github.com/go-ping/ping#linux
@@ -37,18 +39,21 @@ const (
 )
 
 func applyPingGroupRange(ctx context.Context, mech *kernel.Mechanism) error {
-	forwarderNetNS, err := nshandle.Current()
+	targetNetNS, err := nshandle.FromURL(mech.GetNetNSURL())
 	if err != nil {
 		return err
 	}
-	defer func() { _ = forwarderNetNS.Close() }()
+	defer func() { _ = targetNetNS.Close() }()
 
-	var targetNetNS netns.NsHandle
-	targetNetNS, err = nshandle.FromURL(mech.GetNetNSURL())
+	return setPingGroupRange(ctx, targetNetNS)
+}
+
+func setPingGroupRange(ctx context.Context, targetNetNS netns.NsHandle) error {
+	forwarderNetNS, err := nshandle.Current()
 	if err != nil {
 		return err
 	}
-	defer func() { _ = targetNetNS.Close() }()
+	defer func() { _ = forwarderNetNS.Close() }()
 
 	if err = nshandle.RunIn(forwarderNetNS, targetNetNS, func() error {
 		return os.WriteFile(pingGroupRangeFilename, []byte(groupRange), 0o600)
@@ -58,3 +63,12 @@ func applyPingGroupRange(ctx context.Context, mech *kernel.Mechanism) error {
 	log.FromContext(ctx).Debugf("%s was set to %s", pingGroupRangeFilename, groupRange)
 	return nil
 }
+
+// Apply sets the ping group range in targetNetNS, the net NS of the kernel interface of the connection resolved by
+// the caller
+func Apply(ctx context.Context, conn *networkservice.Connection, isClient bool, targetNetNS netns.NsHandle) error {
+	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil && vlanlink.IsConfigurable(ctx, isClient, mechanism) {
+		return setPingGroupRange(ctx, targetNetNS)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package connectioncontextkernel
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/core/next"
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+	"github.com/ljkiraly/sdk/pkg/tools/postpone"
+
+	link "github.com/ljkiraly/sdk-kernel/pkg/kernel"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/connectioncontextkernel/ipcontext/ipaddress"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/connectioncontextkernel/ipcontext/ipneighbors"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/connectioncontextkernel/ipcontext/iprule"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/connectioncontextkernel/ipcontext/routes"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/connectioncontextkernel/mtu"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/connectioncontextkernel/pinggrouprange"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/vlanlink"
+)
+
+type step struct {
+	name  string
+	apply func(ctx context.Context, conn *networkservice.Connection, targetNetNS netns.NsHandle, netlinkHandle *netlink.Handle, l netlink.Link) error
+}
+
+type singlePassServer struct {
+	rules *iprule.Rules
+	steps []step
+}
+
+// NewSinglePassServer provides a NetworkServiceServer that applies the connection context to a kernel interface
+// like NewServer does, but in a single pass: the net NS, its netlink handle and the link are resolved and the link
+// is set up once, then the sysctls, MTU, addresses, neighbors, routes and policy rules are applied to the link in
+// this order logging the duration of every step.
+//
+// There is no client variant: NewClient also sets route_localnet and the iptables NAT template, which are applied
+// by their own elements and are not steps of the single pass.
+func NewSinglePassServer() networkservice.NetworkServiceServer {
+	s := &singlePassServer{
+		rules: iprule.NewRules(),
+	}
+	s.steps = []step{
+		{name: "sysctls", apply: applyPingGroupRange},
+		{name: "mtu", apply: withoutNetNS(mtu.Apply)},
+		{name: "addresses", apply: applyIPAddresses},
+		{name: "neighbors", apply: withoutNetNS(forServer(ipneighbors.Apply))},
+		{name: "routes", apply: withoutNetNS(forServer(routes.Apply))},
+		{name: "rules", apply: withoutNetNS(s.rules.Apply)},
+	}
+	return s
+}
+
+func (s *singlePassServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	postponeCtxFunc := postpone.ContextWithValues(ctx)
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, conn); err != nil {
+		closeCtx, cancelClose := postponeCtxFunc()
+		defer cancelClose()
+
+		if _, closeErr := s.Close(closeCtx, conn); closeErr != nil {
+			err = errors.Wrapf(err, "connection closed with error: %s", closeErr.Error())
+		}
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *singlePassServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if err := s.rules.Delete(ctx, conn); err != nil {
+		log.FromContext(ctx).WithField("connectioncontextkernel", "singlePass").
+			Warnf("failed to delete the policy rules: %+v", err)
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *singlePassServer) apply(ctx context.Context, conn *networkservice.Connection) error {
+	mechanism := kernel.ToMechanism(conn.GetMechanism())
+	if mechanism == nil || !vlanlink.IsConfigurable(ctx, false, mechanism) {
+		return nil
+	}
+	logger := log.FromContext(ctx).WithField("connectioncontextkernel", "singlePass")
+	start := time.Now()
+
+	// all the steps share the net NS and its netlink handle
+	targetNetNS, err := nshandle.FromURL(mechanism.GetNetNSURL())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = targetNetNS.Close() }()
+
+	if link.NetlinkHandleCacheFromContext(ctx) == nil {
+		cache := link.NewNetlinkHandleCache(0)
+		defer cache.Close()
+		ctx = link.WithNetlinkHandleCache(ctx, cache)
+	}
+	netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
+	if err != nil {
+		return err
+	}
+	defer releaseHandle()
+
+	ifName := mechanism.GetInterfaceName()
+	l, err := netlinkHandle.LinkByName(ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find link %s", ifName)
+	}
+	if err = netlinkHandle.LinkSetUp(l); err != nil {
+		return errors.Wrapf(err, "failed to setup link for the interface %v", l)
+	}
+	logger.WithField("step", "link").WithField("duration", time.Since(start)).Debug("completed")
+
+	if err = s.runSteps(ctx, logger, conn, targetNetNS, netlinkHandle, l); err != nil {
+		return err
+	}
+	logger.WithField("link.Name", ifName).WithField("duration", time.Since(start)).Debug("completed")
+	return nil
+}
+
+// runSteps applies the steps in order, it stops at the first failed one
+func (s *singlePassServer) runSteps(ctx context.Context, logger log.Logger, conn *networkservice.Connection,
+	targetNetNS netns.NsHandle, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	for _, st := range s.steps {
+		now := time.Now()
+		if err := st.apply(ctx, conn, targetNetNS, netlinkHandle, l); err != nil {
+			logger.WithField("step", st.name).WithField("duration", time.Since(now)).Errorf("error %+v", err)
+			return errors.Wrapf(err, "failed to apply %s", st.name)
+		}
+		logger.WithField("step", st.name).WithField("duration", time.Since(now)).Debug("completed")
+	}
+	return nil
+}
+
+func forServer(apply func(ctx context.Context, conn *networkservice.Connection, isClient bool, netlinkHandle *netlink.Handle, l netlink.Link) error) func(ctx context.Context, conn *networkservice.Connection, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	return func(ctx context.Context, conn *networkservice.Connection, netlinkHandle *netlink.Handle, l netlink.Link) error {
+		return apply(ctx, conn, false, netlinkHandle, l)
+	}
+}
+
+// withoutNetNS adapts the steps which need the netlink handle only
+func withoutNetNS(apply func(ctx context.Context, conn *networkservice.Connection, netlinkHandle *netlink.Handle, l netlink.Link) error) func(ctx context.Context, conn *networkservice.Connection, targetNetNS netns.NsHandle, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	return func(ctx context.Context, conn *networkservice.Connection, _ netns.NsHandle, netlinkHandle *netlink.Handle, l netlink.Link) error {
+		return apply(ctx, conn, netlinkHandle, l)
+	}
+}
+
+// applyPingGroupRange sets the sysctls, they are set in the net NS and don't need the link
+func applyPingGroupRange(ctx context.Context, conn *networkservice.Connection, targetNetNS netns.NsHandle, _ *netlink.Handle, _ netlink.Link) error {
+	return pinggrouprange.Apply(ctx, conn, false, targetNetNS)
+}
+
+// applyIPAddresses assigns the addresses, IPv6 is enabled for the link in the net NS
+func applyIPAddresses(ctx context.Context, conn *networkservice.Connection, targetNetNS netns.NsHandle, netlinkHandle *netlink.Handle, l netlink.Link) error {
+	return ipaddress.Apply(ctx, conn, false, targetNetNS, netlinkHandle, l)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package connectioncontextkernel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+)
+
+func TestSinglePassServer_StepOrder(t *testing.T) {
+	s, ok := NewSinglePassServer().(*singlePassServer)
+	require.True(t, ok)
+
+	var names []string
+	for _, st := range s.steps {
+		names = append(names, st.name)
+	}
+	require.Equal(t, []string{"sysctls", "mtu", "addresses", "neighbors", "routes", "rules"}, names)
+}
+
+func TestSinglePassServer_RunSteps(t *testing.T) {
+	var applied []string
+	newStep := func(name string, err error) step {
+		return step{
+			name: name,
+			apply: func(context.Context, *networkservice.Connection, netns.NsHandle, *netlink.Handle, netlink.Link) error {
+				applied = append(applied, name)
+				return err
+			},
+		}
+	}
+	ctx := context.Background()
+	conn := &networkservice.Connection{Id: "nsm-conn"}
+
+	s := &singlePassServer{steps: []step{newStep("first", nil), newStep("second", nil), newStep("third", nil)}}
+	require.NoError(t, s.runSteps(ctx, log.FromContext(ctx), conn, netns.None(), nil, nil))
+	require.Equal(t, []string{"first", "second", "third"}, applied)
+
+	// the steps after the failed one are not applied
+	applied = nil
+	stepErr := errors.New("step error")
+	s = &singlePassServer{steps: []step{newStep("first", nil), newStep("second", stepErr), newStep("third", nil)}}
+	err := s.runSteps(ctx, log.FromContext(ctx), conn, netns.None(), nil, nil)
+	require.ErrorIs(t, err, stepErr)
+	require.Contains(t, err.Error(), "failed to apply second")
+	require.Equal(t, []string{"first", "second"}, applied)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package ethernetcontext
+
+import (
+	"context"
+	"net"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/metadata"
+)
+
+type vfInfoKey struct{}
+
+type hwAddrKey struct{}
+
+// storeVFInfo stores the VF attributes found before the connection in per Connection.Id metadata,
+// the already stored value is kept on refresh
+func storeVFInfo(ctx context.Context, isClient bool, vfInfo *netlink.VfInfo) {
+	metadata.Map(ctx, isClient).LoadOrStore(vfInfoKey{}, vfInfo)
+}
+
+// loadAndDeleteVFInfo deletes the VF attributes stored in per Connection.Id metadata,
+// returning the previous value if any
+func loadAndDeleteVFInfo(ctx context.Context, isClient bool) (vfInfo *netlink.VfInfo, ok bool) {
+	rawValue, ok := metadata.Map(ctx, isClient).LoadAndDelete(vfInfoKey{})
+	if !ok {
+		return
+	}
+	vfInfo, ok = rawValue.(*netlink.VfInfo)
+	return vfInfo, ok
+}
+
+// storeHwAddr stores the kernel interface hardware address found before the connection in per Connection.Id
+// metadata, the already stored value is kept on refresh
+func storeHwAddr(ctx context.Context, isClient bool, hwAddr net.HardwareAddr) {
+	metadata.Map(ctx, isClient).LoadOrStore(hwAddrKey{}, hwAddr)
+}
+
+// loadAndDeleteHwAddr deletes the kernel interface hardware address stored in per Connection.Id metadata,
+// returning the previous value if any
+func loadAndDeleteHwAddr(ctx context.Context, isClient bool) (hwAddr net.HardwareAddr, ok bool) {
+	rawValue, ok := metadata.Map(ctx, isClient).LoadAndDelete(hwAddrKey{})
+	if !ok {
+		return
+	}
+	hwAddr, ok = rawValue.(net.HardwareAddr)
+	return hwAddr, ok
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package ethernetcontext
+
+import (
+	"github.com/vishvananda/netlink"
+)
+
+// LinkState is a VF link state configured via the parent PF
+type LinkState uint32
+
+const (
+	// LinkStateAuto - VF link state follows the PF link state
+	LinkStateAuto = LinkState(netlink.VF_LINK_STATE_AUTO)
+	// LinkStateEnable - VF link is always up
+	LinkStateEnable = LinkState(netlink.VF_LINK_STATE_ENABLE)
+	// LinkStateDisable - VF link is always down
+	LinkStateDisable = LinkState(netlink.VF_LINK_STATE_DISABLE)
+)
+
+type txRate struct {
+	minRate uint32
+	maxRate uint32
+}
+
+type vfOptions struct {
+	spoofCheck *bool
+	trust      *bool
+	linkState  *LinkState
+	txRate     *txRate
+	vlanQoS    int
+	vlanProto  netlink.VlanProtocol
+}
+
+// Option is an option pattern for NewVFClient, NewVFServer
+type Option func(o *vfOptions)
+
+// WithSpoofCheck - enables or disables MAC spoof checking on the VF
+func WithSpoofCheck(enabled bool) Option {
+	return func(o *vfOptions) {
+		o.spoofCheck = &enabled
+	}
+}
+
+// WithTrust - sets the VF trust mode
+func WithTrust(enabled bool) Option {
+	return func(o *vfOptions) {
+		o.trust = &enabled
+	}
+}
+
+// WithLinkState - sets the VF link state (auto/enable/disable)
+func WithLinkState(state LinkState) Option {
+	return func(o *vfOptions) {
+		o.linkState = &state
+	}
+}
+
+// WithTxRate - sets the VF min and max TX rate in Mbps, 0 means unlimited
+func WithTxRate(minRate, maxRate uint32) Option {
+	return func(o *vfOptions) {
+		o.txRate = &txRate{
+			minRate: minRate,
+			maxRate: maxRate,
+		}
+	}
+}
+
+// WithVLANQoS - sets the VLAN QoS priority used together with the EthernetContext VLAN tag
+func WithVLANQoS(qos int) Option {
+	return func(o *vfOptions) {
+		o.vlanQoS = qos
+	}
+}
+
+// WithVLANProtocol - sets the VLAN protocol used together with the EthernetContext VLAN tag,
+// netlink.VLAN_PROTOCOL_8021AD enables QinQ on the VF
+func WithVLANProtocol(proto netlink.VlanProtocol) Option {
+	return func(o *vfOptions) {
+		o.vlanProto = proto
+	}
+}
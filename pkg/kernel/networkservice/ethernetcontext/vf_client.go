@@ -32,17 +32,20 @@ import (
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/ljkiraly/sdk/pkg/networkservice/core/next"
-	"github.com/ljkiraly/sdk/pkg/tools/log"
 	"github.com/ljkiraly/sdk/pkg/tools/postpone"
-
-	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/vfconfig"
 )
 
-type vfEthernetClient struct{}
+type vfEthernetClient struct {
+	options *vfOptions
+}
 
 // NewVFClient returns a new VF ethernet context client chain element
-func NewVFClient() networkservice.NetworkServiceClient {
-	return &vfEthernetClient{}
+func NewVFClient(opts ...Option) networkservice.NetworkServiceClient {
+	o := &vfOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &vfEthernetClient{options: o}
 }
 
 func (i *vfEthernetClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
@@ -53,38 +56,29 @@ func (i *vfEthernetClient) Request(ctx context.Context, request *networkservice.
 		return nil, err
 	}
 
-	if vfConfig, ok := vfconfig.Load(ctx, true); ok {
-		if err := vfCreate(ctx, vfConfig, conn, true); err != nil {
-			closeCtx, cancelClose := postponeCtxFunc()
-			defer cancelClose()
-
-			if _, closeErr := i.Close(closeCtx, conn, opts...); closeErr != nil {
-				err = errors.Wrapf(err, "connection closed with error: %s", closeErr.Error())
-			}
+	if err := vfSetup(ctx, conn, i.options, true); err != nil {
+		closeCtx, cancelClose := postponeCtxFunc()
+		defer cancelClose()
 
-			return nil, err
+		if _, closeErr := i.Close(closeCtx, conn, opts...); closeErr != nil {
+			err = errors.Wrapf(err, "connection closed with error: %s", closeErr.Error())
 		}
-	} else {
-		if err := setKernelHwAddress(ctx, conn, true); err != nil {
-			closeCtx, cancelClose := postponeCtxFunc()
-			defer cancelClose()
 
-			if _, closeErr := i.Close(closeCtx, conn); closeErr != nil {
-				err = errors.Wrapf(err, "connection closed with error: %s", closeErr.Error())
-			}
-
-			return nil, err
-		}
+		return nil, err
 	}
 
 	return conn, nil
 }
 
 func (i *vfEthernetClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
-	if vfConfig, ok := vfconfig.Load(ctx, true); ok {
-		if err := vfCleanup(ctx, vfConfig); err != nil {
-			log.FromContext(ctx).Errorf("vfEthernetClient vfClear: %v", err.Error())
+	vfErr := vfClose(ctx, conn, i.options, true)
+
+	rv, err := next.Client(ctx).Close(ctx, conn, opts...)
+	if vfErr != nil {
+		if err != nil {
+			return nil, errors.Wrapf(vfErr, "close failed with error: %s", err.Error())
 		}
+		return nil, vfErr
 	}
-	return next.Client(ctx).Close(ctx, conn, opts...)
+	return rv, err
 }
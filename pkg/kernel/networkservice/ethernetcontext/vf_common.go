@@ -32,6 +32,7 @@ import (
 	"github.com/ljkiraly/sdk/pkg/tools/log"
 	"github.com/pkg/errors"
 	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
 
 	link "github.com/ljkiraly/sdk-kernel/pkg/kernel"
 	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/vfconfig"
@@ -39,11 +40,31 @@ import (
 
 func setKernelHwAddress(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
 	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil {
-		netlinkHandle, err := link.GetNetlinkHandle(mechanism.GetNetNSURL())
+		ethernetContext := conn.GetContext().GetEthernetContext()
+		if ethernetContext == nil {
+			return nil
+		}
+
+		var macAddrString string
+		if isClient {
+			macAddrString = ethernetContext.GetDstMac()
+		} else {
+			macAddrString = ethernetContext.GetSrcMac()
+		}
+		if macAddrString == "" {
+			return nil
+		}
+
+		macAddr, err := net.ParseMAC(macAddrString)
+		if err != nil {
+			return errors.Wrapf(err, "invalid MAC address: %v", macAddrString)
+		}
+
+		netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
 		if err != nil {
 			return err
 		}
-		defer netlinkHandle.Close()
+		defer releaseHandle()
 
 		ifName := mechanism.GetInterfaceName()
 		l, err := netlinkHandle.LinkByName(ifName)
@@ -51,50 +72,73 @@ func setKernelHwAddress(ctx context.Context, conn *networkservice.Connection, is
 			return errors.Wrapf(err, "failed to find link %s", ifName)
 		}
 
-		if ethernetContext := conn.GetContext().GetEthernetContext(); ethernetContext != nil {
-			var macAddrString string
-			if isClient {
-				macAddrString = ethernetContext.GetDstMac()
-			} else {
-				macAddrString = ethernetContext.GetSrcMac()
-			}
+		storeHwAddr(ctx, isClient, l.Attrs().HardwareAddr)
 
-			if macAddrString != "" {
-				now := time.Now()
-				var macAddr net.HardwareAddr
-				macAddr, err := net.ParseMAC(macAddrString)
-				if err != nil {
-					return errors.Wrapf(err, "invalid MAC address: %v", macAddrString)
-				}
-				if bytes.Equal([]byte(macAddr), []byte(l.Attrs().HardwareAddr)) {
-					return nil
-				}
-				if err = netlinkHandle.LinkSetDown(l); err != nil {
-					return errors.Wrapf(err, "failed to disable link device %s", l.Attrs().Name)
-				}
-				if err = netlinkHandle.LinkSetHardwareAddr(l, macAddr); err != nil {
-					return errors.Wrapf(err, "failed to set MAC address for the VF: %v", macAddr)
-				}
-				if err = netlinkHandle.LinkSetUp(l); err != nil {
-					return errors.Wrapf(err, "failed to setup link for the interface %v", l)
-				}
-				log.FromContext(ctx).
-					WithField("link.Name", l.Attrs().Name).
-					WithField("MACAddr", macAddrString).
-					WithField("duration", time.Since(now)).
-					WithField("netlink", "LinkSetHardwareAddr").Debug("completed")
-			}
+		return linkSetHardwareAddr(ctx, netlinkHandle, l, macAddr)
+	}
+	return nil
+}
+
+func restoreKernelHwAddress(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	hwAddr, ok := loadAndDeleteHwAddr(ctx, isClient)
+	if !ok || len(hwAddr) == 0 {
+		return nil
+	}
+	if mechanism := kernel.ToMechanism(conn.GetMechanism()); mechanism != nil {
+		netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
+		if err != nil {
+			return err
 		}
+		defer releaseHandle()
+
+		ifName := mechanism.GetInterfaceName()
+		l, err := netlinkHandle.LinkByName(ifName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find link %s", ifName)
+		}
+
+		return linkSetHardwareAddr(ctx, netlinkHandle, l, hwAddr)
 	}
 	return nil
 }
 
-func vfCreate(ctx context.Context, vfConfig *vfconfig.VFConfig, conn *networkservice.Connection, isClient bool) error {
-	pfLink, err := netlink.LinkByName(vfConfig.PFInterfaceName)
-	if err != nil {
-		return errors.Wrapf(err, "failed to get PF network interface: %v", vfConfig.PFInterfaceName)
+// linkSetHardwareAddr sets the hardware address of the link. The link is set down and up again only if the
+// driver refuses to change the address of a running interface, so a refresh doesn't cause a link flap.
+func linkSetHardwareAddr(ctx context.Context, netlinkHandle *netlink.Handle, l netlink.Link, macAddr net.HardwareAddr) error {
+	if bytes.Equal([]byte(macAddr), []byte(l.Attrs().HardwareAddr)) {
+		return nil
 	}
 
+	now := time.Now()
+	err := netlinkHandle.LinkSetHardwareAddr(l, macAddr)
+	if errors.Is(err, unix.EBUSY) {
+		if err = netlinkHandle.LinkSetDown(l); err != nil {
+			return errors.Wrapf(err, "failed to disable link device %s", l.Attrs().Name)
+		}
+		if err = netlinkHandle.LinkSetHardwareAddr(l, macAddr); err != nil {
+			return errors.Wrapf(err, "failed to set MAC address for the interface: %v", macAddr)
+		}
+		if err = netlinkHandle.LinkSetUp(l); err != nil {
+			return errors.Wrapf(err, "failed to setup link for the interface %v", l)
+		}
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to set MAC address for the interface: %v", macAddr)
+	}
+	log.FromContext(ctx).
+		WithField("link.Name", l.Attrs().Name).
+		WithField("MACAddr", macAddr.String()).
+		WithField("duration", time.Since(now)).
+		WithField("netlink", "LinkSetHardwareAddr").Debug("completed")
+	return nil
+}
+
+func vfCreate(ctx context.Context, vfConfig *vfconfig.VFConfig, conn *networkservice.Connection, o *vfOptions, isClient bool) error {
+	desired := &vfState{
+		spoofCheck: o.spoofCheck,
+		trust:      o.trust,
+		linkState:  o.linkState,
+		txRate:     o.txRate,
+	}
 	if ethernetContext := conn.GetContext().GetEthernetContext(); ethernetContext != nil {
 		var macAddrString string
 		if isClient {
@@ -103,66 +147,81 @@ func vfCreate(ctx context.Context, vfConfig *vfconfig.VFConfig, conn *networkser
 			macAddrString = ethernetContext.GetSrcMac()
 		}
 		if macAddrString != "" {
-			now := time.Now()
-			var macAddr net.HardwareAddr
-			macAddr, err = net.ParseMAC(macAddrString)
+			macAddr, err := net.ParseMAC(macAddrString)
 			if err != nil {
 				return errors.Wrapf(err, "invalid MAC address: %v", macAddrString)
 			}
-			if err = netlink.LinkSetVfHardwareAddr(pfLink, vfConfig.VFNum, macAddr); err != nil {
-				return errors.Wrapf(err, "failed to set MAC address for the VF: %v", macAddr)
-			}
-			log.FromContext(ctx).
-				WithField("pfLink", pfLink.Attrs().Name).
-				WithField("vf", vfConfig.VFNum).
-				WithField("MACAddr", macAddrString).
-				WithField("duration", time.Since(now)).
-				WithField("netlink", "LinkSetVfHardwareAddr").Debug("completed")
+			desired.mac = macAddr
 		}
+		// the VLAN is reset if the tag is removed on refresh, the QoS and the protocol apply to a tagged VLAN only
+		desired.vlan = &vfVlan{}
 		if vlanTag := int(ethernetContext.GetVlanTag()); vlanTag != 0 {
-			now := time.Now()
-			if err = netlink.LinkSetVfVlan(pfLink, vfConfig.VFNum, vlanTag); err != nil {
-				return errors.Wrapf(err, "failed to set VLAN for the VF: %v", vlanTag)
+			desired.vlan = &vfVlan{
+				vlan:  vlanTag,
+				qos:   o.vlanQoS,
+				proto: o.vlanProto,
 			}
-			log.FromContext(ctx).
-				WithField("pfLink", pfLink.Attrs().Name).
-				WithField("vf", vfConfig.VFNum).
-				WithField("vlan", vlanTag).
-				WithField("duration", time.Since(now)).
-				WithField("netlink", "LinkSetVfVlan").Debug("completed")
 		}
 	}
 
-	return nil
+	return reconcileVF(ctx, vfConfig, desired, func(current *netlink.VfInfo) {
+		storeVFInfo(ctx, isClient, current)
+	})
 }
 
-func vfCleanup(ctx context.Context, vfConfig *vfconfig.VFConfig) error {
-	pfLink, err := netlink.LinkByName(vfConfig.PFInterfaceName)
-	if err != nil {
-		return errors.Wrapf(err, "failed to get PF network interface: %v", vfConfig.PFInterfaceName)
+func vfCleanup(ctx context.Context, vfConfig *vfconfig.VFConfig, o *vfOptions, isClient bool) error {
+	// Restore the VF attributes found before the connection, fall back to zero MAC and default VLAN
+	// if they are unknown (e.g. after forwarder restart)
+	vfInfo, ok := loadAndDeleteVFInfo(ctx, isClient)
+	if !ok {
+		vfInfo = &netlink.VfInfo{
+			ID: vfConfig.VFNum,
+		}
+		o = &vfOptions{}
+	}
+	if len(vfInfo.Mac) == 0 {
+		vfInfo.Mac = make(net.HardwareAddr, 32)
 	}
 
-	now := time.Now()
-	if err = netlink.LinkSetVfHardwareAddr(pfLink, vfConfig.VFNum, make([]byte, 32)); err != nil {
-		return errors.Wrap(err, "failed to set zero MAC address for the VF")
+	desired := &vfState{
+		mac: vfInfo.Mac,
+		vlan: &vfVlan{
+			vlan:  vfInfo.Vlan,
+			qos:   vfInfo.Qos,
+			proto: netlink.VlanProtocol(vfInfo.VlanProto),
+		},
+	}
+	if o.spoofCheck != nil {
+		desired.spoofCheck = &vfInfo.Spoofchk
+	}
+	if o.trust != nil {
+		trust := vfInfo.Trust == 1
+		desired.trust = &trust
+	}
+	if o.linkState != nil {
+		linkState := LinkState(vfInfo.LinkState)
+		desired.linkState = &linkState
+	}
+	if o.txRate != nil {
+		desired.txRate = &txRate{
+			minRate: vfInfo.MinTxRate,
+			maxRate: vfInfo.MaxTxRate,
+		}
 	}
-	log.FromContext(ctx).
-		WithField("pfLink", pfLink.Attrs().Name).
-		WithField("vf", vfConfig.VFNum).
-		WithField("MACAddr", "00:00:00:00:00:00").
-		WithField("duration", time.Since(now)).
-		WithField("netlink", "LinkSetVfHardwareAddr").Debug("completed")
 
-	now = time.Now()
-	if err = netlink.LinkSetVfVlan(pfLink, vfConfig.VFNum, 0); err != nil {
-		return errors.Wrapf(err, "failed to set default VLAN for the VF")
+	return reconcileVF(ctx, vfConfig, desired, nil)
+}
+
+func vfSetup(ctx context.Context, conn *networkservice.Connection, o *vfOptions, isClient bool) error {
+	if vfConfig, ok := vfconfig.Load(ctx, isClient); ok {
+		return vfCreate(ctx, vfConfig, conn, o, isClient)
 	}
-	log.FromContext(ctx).
-		WithField("pfLink", pfLink.Attrs().Name).
-		WithField("vf", vfConfig.VFNum).
-		WithField("vlan", 0).
-		WithField("duration", time.Since(now)).
-		WithField("netlink", "LinkSetVfVlan").Debug("completed")
+	return setKernelHwAddress(ctx, conn, isClient)
+}
 
-	return nil
+func vfClose(ctx context.Context, conn *networkservice.Connection, o *vfOptions, isClient bool) error {
+	if vfConfig, ok := vfconfig.Load(ctx, isClient); ok {
+		return errors.Wrap(vfCleanup(ctx, vfConfig, o, isClient), "failed to clean up VF")
+	}
+	return errors.Wrap(restoreKernelHwAddress(ctx, conn, isClient), "failed to restore hardware address")
 }
@@ -32,17 +32,20 @@ import (
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/ljkiraly/sdk/pkg/networkservice/core/next"
-	"github.com/ljkiraly/sdk/pkg/tools/log"
 	"github.com/ljkiraly/sdk/pkg/tools/postpone"
-
-	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/vfconfig"
 )
 
-type vfEthernetContextServer struct{}
+type vfEthernetContextServer struct {
+	options *vfOptions
+}
 
 // NewVFServer returns a new VF ethernet context server chain element
-func NewVFServer() networkservice.NetworkServiceServer {
-	return &vfEthernetContextServer{}
+func NewVFServer(opts ...Option) networkservice.NetworkServiceServer {
+	o := &vfOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &vfEthernetContextServer{options: o}
 }
 
 func (s *vfEthernetContextServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
@@ -53,37 +56,29 @@ func (s *vfEthernetContextServer) Request(ctx context.Context, request *networks
 		return nil, err
 	}
 
-	if vfConfig, ok := vfconfig.Load(ctx, false); ok {
-		if err := vfCreate(ctx, vfConfig, conn, false); err != nil {
-			closeCtx, cancelClose := postponeCtxFunc()
-			defer cancelClose()
+	if err := vfSetup(ctx, conn, s.options, false); err != nil {
+		closeCtx, cancelClose := postponeCtxFunc()
+		defer cancelClose()
 
-			if _, closeErr := s.Close(closeCtx, conn); closeErr != nil {
-				err = errors.Wrapf(err, "connection closed with error: %s", closeErr.Error())
-			}
-
-			return nil, err
+		if _, closeErr := s.Close(closeCtx, conn); closeErr != nil {
+			err = errors.Wrapf(err, "connection closed with error: %s", closeErr.Error())
 		}
-	} else {
-		if err := setKernelHwAddress(ctx, conn, false); err != nil {
-			closeCtx, cancelClose := postponeCtxFunc()
-			defer cancelClose()
-
-			if _, closeErr := s.Close(closeCtx, conn); closeErr != nil {
-				err = errors.Wrapf(err, "connection closed with error: %s", closeErr.Error())
-			}
 
-			return nil, err
-		}
+		return nil, err
 	}
+
 	return conn, nil
 }
 
 func (s *vfEthernetContextServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
-	if vfConfig, ok := vfconfig.Load(ctx, false); ok {
-		if err := vfCleanup(ctx, vfConfig); err != nil {
-			log.FromContext(ctx).Errorf("vfEthernetContextServer vfClear: %v", err.Error())
+	vfErr := vfClose(ctx, conn, s.options, false)
+
+	rv, err := next.Server(ctx).Close(ctx, conn)
+	if vfErr != nil {
+		if err != nil {
+			return nil, errors.Wrapf(vfErr, "close failed with error: %s", err.Error())
 		}
+		return nil, vfErr
 	}
-	return next.Server(ctx).Close(ctx, conn)
+	return rv, err
 }
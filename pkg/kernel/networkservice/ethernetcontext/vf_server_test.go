@@ -0,0 +1,118 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build perm
+// +build perm
+
+package ethernetcontext_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/core/chain"
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/metadata"
+
+	link "github.com/ljkiraly/sdk-kernel/pkg/kernel"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/ethernetcontext"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/netlinkcache"
+)
+
+const ifName = "nsm-eth"
+
+func TestVFServer_KernelHwAddressPerm(t *testing.T) {
+	target := newNSHandleWithVeth(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	l, err := handle.LinkByName(ifName)
+	require.NoError(t, err)
+	origHwAddr := l.Attrs().HardwareAddr
+
+	cache := link.NewNetlinkHandleCache(time.Minute)
+	defer cache.Close()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		netlinkcache.NewServer(cache),
+		ethernetcontext.NewVFServer(),
+	)
+
+	mechanism := kernel.New(fmt.Sprintf("fd://%d", int(target)))
+	kernel.ToMechanism(mechanism).SetInterfaceName(ifName)
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:        "nsm-conn",
+			Mechanism: mechanism,
+			Context: &networkservice.ConnectionContext{
+				EthernetContext: &networkservice.EthernetContext{SrcMac: "0a:00:00:00:00:01"},
+			},
+		},
+	}
+
+	conn, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+
+	l, err = handle.LinkByName(ifName)
+	require.NoError(t, err)
+	require.Equal(t, net.HardwareAddr{0x0a, 0, 0, 0, 0, 0x01}, l.Attrs().HardwareAddr)
+
+	// a refresh with the same address is a no-op
+	request.Connection = conn
+	conn, err = server.Request(context.Background(), request)
+	require.NoError(t, err)
+
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+
+	l, err = handle.LinkByName(ifName)
+	require.NoError(t, err)
+	require.Equal(t, origHwAddr, l.Attrs().HardwareAddr)
+}
+
+func newNSHandleWithVeth(t *testing.T) netns.NsHandle {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	baseHandle, err := netns.Get()
+	require.NoError(t, err)
+	defer func() {
+		_ = netns.Set(baseHandle)
+		_ = baseHandle.Close()
+	}()
+
+	newHandle, err := netns.New()
+	require.NoError(t, err)
+
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  ifName + "-peer",
+	}))
+	return newHandle
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package ethernetcontext
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+)
+
+type vfVlan struct {
+	vlan  int
+	qos   int
+	proto netlink.VlanProtocol
+}
+
+// vfState is a desired state of the VF attributes, nil fields are left unchanged
+type vfState struct {
+	mac        net.HardwareAddr
+	vlan       *vfVlan
+	spoofCheck *bool
+	trust      *bool
+	linkState  *LinkState
+	txRate     *txRate
+}
+
+// reconcileVF compares the desired VF attributes with the current ones reported by the PF and programs
+// only the differing ones. Every differing attribute is programmed even if some of them fail, the errors are
+// combined. onCurrent, if set, is called with the current VF attributes before any change.
+func reconcileVF(ctx context.Context, vfConfig *vfconfig.VFConfig, desired *vfState, onCurrent func(current *netlink.VfInfo)) error {
+	pfLink, current, err := getPFLinkAndVFInfo(vfConfig.PFInterfaceName, vfConfig.VFNum)
+	if err != nil {
+		return err
+	}
+	if onCurrent != nil {
+		onCurrent(current)
+	}
+
+	logger := log.FromContext(ctx).
+		WithField("pfLink", pfLink.Attrs().Name).
+		WithField("vf", vfConfig.VFNum)
+
+	if desired.mac != nil && !bytes.Equal(desired.mac, current.Mac) {
+		now := time.Now()
+		if setErr := netlink.LinkSetVfHardwareAddr(pfLink, vfConfig.VFNum, desired.mac); setErr != nil {
+			err = appendError(err, errors.Wrapf(setErr, "failed to set MAC address for the VF: %v", desired.mac))
+		} else {
+			logger.WithField("MACAddr", desired.mac.String()).
+				WithField("duration", time.Since(now)).
+				WithField("netlink", "LinkSetVfHardwareAddr").Debug("completed")
+		}
+	}
+	if desired.vlan != nil && !vlanEquals(desired.vlan, current) {
+		err = appendError(err, setVFVlan(logger, pfLink, vfConfig.VFNum, desired.vlan))
+	}
+	return appendError(err, reconcileVFAttributes(logger, pfLink, vfConfig.VFNum, desired, current))
+}
+
+func reconcileVFAttributes(logger log.Logger, pfLink netlink.Link, vfNum int, desired *vfState, current *netlink.VfInfo) (err error) {
+	if desired.spoofCheck != nil && *desired.spoofCheck != current.Spoofchk {
+		now := time.Now()
+		if setErr := netlink.LinkSetVfSpoofchk(pfLink, vfNum, *desired.spoofCheck); setErr != nil {
+			err = appendError(err, errors.Wrapf(setErr, "failed to set spoof check for the VF: %v", *desired.spoofCheck))
+		} else {
+			logger.WithField("spoofchk", *desired.spoofCheck).
+				WithField("duration", time.Since(now)).
+				WithField("netlink", "LinkSetVfSpoofchk").Debug("completed")
+		}
+	}
+	if desired.trust != nil && *desired.trust != (current.Trust == 1) {
+		now := time.Now()
+		if setErr := netlink.LinkSetVfTrust(pfLink, vfNum, *desired.trust); setErr != nil {
+			err = appendError(err, errors.Wrapf(setErr, "failed to set trust for the VF: %v", *desired.trust))
+		} else {
+			logger.WithField("trust", *desired.trust).
+				WithField("duration", time.Since(now)).
+				WithField("netlink", "LinkSetVfTrust").Debug("completed")
+		}
+	}
+	if desired.linkState != nil && uint32(*desired.linkState) != current.LinkState {
+		now := time.Now()
+		if setErr := netlink.LinkSetVfState(pfLink, vfNum, uint32(*desired.linkState)); setErr != nil {
+			err = appendError(err, errors.Wrapf(setErr, "failed to set link state for the VF: %v", *desired.linkState))
+		} else {
+			logger.WithField("linkState", *desired.linkState).
+				WithField("duration", time.Since(now)).
+				WithField("netlink", "LinkSetVfState").Debug("completed")
+		}
+	}
+	if desired.txRate != nil && (desired.txRate.minRate != current.MinTxRate || desired.txRate.maxRate != current.MaxTxRate) {
+		now := time.Now()
+		if setErr := netlink.LinkSetVfRate(pfLink, vfNum, int(desired.txRate.minRate), int(desired.txRate.maxRate)); setErr != nil {
+			err = appendError(err, errors.Wrapf(setErr, "failed to set TX rate for the VF: min %v, max %v", desired.txRate.minRate, desired.txRate.maxRate))
+		} else {
+			logger.WithField("minTxRate", desired.txRate.minRate).
+				WithField("maxTxRate", desired.txRate.maxRate).
+				WithField("duration", time.Since(now)).
+				WithField("netlink", "LinkSetVfRate").Debug("completed")
+		}
+	}
+	return err
+}
+
+// appendError combines the error with the next one, both of them are kept and can be found with errors.As
+func appendError(err, next error) error {
+	return stderrors.Join(err, next)
+}
+
+func setVFVlan(logger log.Logger, pfLink netlink.Link, vfNum int, vlan *vfVlan) (err error) {
+	now := time.Now()
+	op := "LinkSetVfVlanQos"
+	if isQinQ(vlan.proto) {
+		op = "LinkSetVfVlanQosProto"
+		err = netlink.LinkSetVfVlanQosProto(pfLink, vfNum, vlan.vlan, vlan.qos, int(vlan.proto))
+	} else {
+		err = netlink.LinkSetVfVlanQos(pfLink, vfNum, vlan.vlan, vlan.qos)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to set VLAN for the VF: %v (qos %v, proto %v)", vlan.vlan, vlan.qos, vlan.proto)
+	}
+	logger.WithField("vlan", vlan.vlan).
+		WithField("qos", vlan.qos).
+		WithField("proto", vlan.proto).
+		WithField("duration", time.Since(now)).
+		WithField("netlink", op).Debug("completed")
+	return nil
+}
+
+func vlanEquals(vlan *vfVlan, current *netlink.VfInfo) bool {
+	return vlan.vlan == current.Vlan && vlan.qos == current.Qos &&
+		isQinQ(vlan.proto) == isQinQ(netlink.VlanProtocol(current.VlanProto))
+}
+
+func isQinQ(proto netlink.VlanProtocol) bool {
+	return proto != netlink.VLAN_PROTOCOL_UNKNOWN && proto != netlink.VLAN_PROTOCOL_8021Q
+}
+
+func getPFLinkAndVFInfo(pfName string, vfNum int) (netlink.Link, *netlink.VfInfo, error) {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list network interfaces")
+	}
+	for _, l := range links {
+		if l.Attrs().Name != pfName {
+			continue
+		}
+		for i := range l.Attrs().Vfs {
+			if l.Attrs().Vfs[i].ID == vfNum {
+				vfInfo := l.Attrs().Vfs[i]
+				return l, &vfInfo, nil
+			}
+		}
+		return nil, nil, errors.Errorf("failed to find VF %d on PF network interface: %v", vfNum, pfName)
+	}
+	return nil, nil, errors.Errorf("failed to get PF network interface: %v", pfName)
+}
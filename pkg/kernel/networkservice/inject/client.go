@@ -23,7 +23,6 @@ package inject
 
 import (
 	"context"
-	"sync"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
@@ -38,18 +37,24 @@ import (
 )
 
 type injectClient struct {
-	vfRefCountMap   map[string]int
-	vfRefCountMutex sync.Mutex
+	refCounts *refCountStore
+	options   *options
 }
 
 // NewClient - returns a new networkservice.NetworkServiceClient that moves given network
 // interface into the Endpoint's pod network namespace on Request and back to Forwarder's
 // network namespace on Close
-func NewClient() networkservice.NetworkServiceClient {
-	return &injectClient{vfRefCountMap: make(map[string]int)}
+func NewClient(opts ...Option) networkservice.NetworkServiceClient {
+	o := newOptions(opts...)
+	return &injectClient{
+		refCounts: newRefCountStoreWithOptions(o),
+		options:   o,
+	}
 }
 
 func (c *injectClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	c.refCounts.touch(request.GetConnection().GetId())
+
 	var isEstablished bool
 	if vfConfig, ok := vfconfig.Load(ctx, metadata.IsClient(c)); ok {
 		isEstablished = int(vfConfig.ContNetNS) != 0
@@ -63,7 +68,7 @@ func (c *injectClient) Request(ctx context.Context, request *networkservice.Netw
 	}
 
 	if !isEstablished {
-		if err := move(ctx, conn, c.vfRefCountMap, &c.vfRefCountMutex, metadata.IsClient(c), false); err != nil {
+		if err := move(ctx, conn, c.refCounts, c.options, metadata.IsClient(c), false); err != nil {
 			closeCtx, cancelClose := postponeCtxFunc()
 			defer cancelClose()
 
@@ -79,7 +84,7 @@ func (c *injectClient) Request(ctx context.Context, request *networkservice.Netw
 }
 
 func (c *injectClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
-	injectErr := move(ctx, conn, c.vfRefCountMap, &c.vfRefCountMutex, metadata.IsClient(c), true)
+	injectErr := move(ctx, conn, c.refCounts, c.options, metadata.IsClient(c), true)
 	if injectErr != nil {
 		return nil, injectErr
 	}
@@ -24,8 +24,7 @@ package inject
 import (
 	"context"
 	"fmt"
-	"strings"
-	"sync"
+	"hash/fnv"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
@@ -33,90 +32,97 @@ import (
 	"github.com/pkg/errors"
 	"github.com/vishvananda/netlink"
 	"github.com/vishvananda/netns"
-	"golang.org/x/sys/unix"
 
 	kernellink "github.com/ljkiraly/sdk-kernel/pkg/kernel"
 	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/vfconfig"
 	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
 )
 
-func moveInterfaceToAnotherNamespace(ifName string, fromNetNS, toNetNS netns.NsHandle, logger log.Logger) error {
+// MoveInterfaceToAnotherNamespace moves the ifName interface from the fromNetNS to the toNetNS
+func MoveInterfaceToAnotherNamespace(ifName string, fromNetNS, toNetNS netns.NsHandle, logger log.Logger) error {
 	handle, err := netlink.NewHandleAt(fromNetNS)
 	if err != nil {
-		return errors.Wrap(err, "failed to create netlink fromNetNS handle")
+		return errors.Wrap(kernellink.ToLinkError(err, ifName, fromNetNS.String()), "failed to create netlink fromNetNS handle")
 	}
+	defer handle.Close()
 
 	link, err := handle.LinkByName(ifName)
 	if err != nil {
-		return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+		return errors.Wrapf(kernellink.ToLinkError(err, ifName, fromNetNS.String()), "failed to get net interface: %v", ifName)
 	}
 
 	if err := handle.LinkSetNsFd(link, int(toNetNS)); err != nil {
-		return errors.Wrapf(err, "failed to move net interface to net NS: %v %v", ifName, toNetNS)
+		return errors.Wrapf(kernellink.ToLinkError(err, ifName, toNetNS.String()), "failed to move net interface to net NS: %v %v", ifName, toNetNS)
 	}
 	logger.Debugf("Interface %v moved from netNS %v into the netNS %v", ifName, fromNetNS, toNetNS)
 	return nil
 }
 
-func renameInterface(origIfName, desiredIfName string, targetNetNS netns.NsHandle, logger log.Logger) error {
+// RenameInterface sets down the origIfName interface in the targetNetNS and renames it to desiredIfName
+func RenameInterface(origIfName, desiredIfName string, targetNetNS netns.NsHandle, logger log.Logger) error {
 	handle, err := netlink.NewHandleAt(targetNetNS)
 	if err != nil {
-		return errors.Wrap(err, "failed to create netlink targetNetNS handle")
+		return errors.Wrap(kernellink.ToLinkError(err, origIfName, targetNetNS.String()), "failed to create netlink targetNetNS handle")
 	}
+	defer handle.Close()
 
 	link, err := handle.LinkByName(origIfName)
 	if err != nil {
-		return errors.Wrapf(err, "failed to get net interface: %v", origIfName)
+		return errors.Wrapf(kernellink.ToLinkError(err, origIfName, targetNetNS.String()), "failed to get net interface: %v", origIfName)
 	}
 
 	if err = handle.LinkSetDown(link); err != nil {
-		return errors.Wrapf(err, "failed to down net interface: %v -> %v", origIfName, desiredIfName)
+		return errors.Wrapf(kernellink.ToLinkError(err, origIfName, targetNetNS.String()), "failed to down net interface: %v -> %v", origIfName, desiredIfName)
 	}
 
 	if err = handle.LinkSetName(link, desiredIfName); err != nil {
-		return errors.Wrapf(err, "failed to rename net interface: %v -> %v", origIfName, desiredIfName)
+		return errors.Wrapf(kernellink.ToLinkError(err, desiredIfName, targetNetNS.String()), "failed to rename net interface: %v -> %v", origIfName, desiredIfName)
 	}
 	logger.Debugf("Interface renamed %v -> %v in netNS %v", origIfName, desiredIfName, targetNetNS)
 	return nil
 }
 
-func upInterface(ifName string, targetNetNS netns.NsHandle, logger log.Logger) error {
+// UpInterface sets up the ifName interface in the targetNetNS
+func UpInterface(ifName string, targetNetNS netns.NsHandle, logger log.Logger) error {
 	handle, err := netlink.NewHandleAt(targetNetNS)
 	if err != nil {
-		return errors.Wrap(err, "failed to create netlink NS handle")
+		return errors.Wrap(kernellink.ToLinkError(err, ifName, targetNetNS.String()), "failed to create netlink NS handle")
 	}
+	defer handle.Close()
 
 	link, err := handle.LinkByName(ifName)
 	if err != nil {
-		return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+		return errors.Wrapf(kernellink.ToLinkError(err, ifName, targetNetNS.String()), "failed to get net interface: %v", ifName)
 	}
 
 	if err = handle.LinkSetUp(link); err != nil {
-		return errors.Wrapf(err, "failed to up net interface: %v", ifName)
+		return errors.Wrapf(kernellink.ToLinkError(err, ifName, targetNetNS.String()), "failed to up net interface: %v", ifName)
 	}
 	logger.Debugf("Administrative state for interface %v is set UP in netNS %v", ifName, targetNetNS)
 	return nil
 }
 
-func deleteInterface(ifName string, targetNetNS netns.NsHandle, logger log.Logger) error {
+// DeleteInterface deletes the ifName interface from the targetNetNS
+func DeleteInterface(ifName string, targetNetNS netns.NsHandle, logger log.Logger) error {
 	handle, err := netlink.NewHandleAt(targetNetNS)
 	if err != nil {
-		return errors.Wrap(err, "failed to create netlink NS handle")
+		return errors.Wrap(kernellink.ToLinkError(err, ifName, targetNetNS.String()), "failed to create netlink NS handle")
 	}
+	defer handle.Close()
 
 	link, err := handle.LinkByName(ifName)
 	if err != nil {
-		return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+		return errors.Wrapf(kernellink.ToLinkError(err, ifName, targetNetNS.String()), "failed to get net interface: %v", ifName)
 	}
 
 	if err = handle.LinkDel(link); err != nil {
-		return errors.Wrapf(err, "failed to delete interface: %v", ifName)
+		return errors.Wrapf(kernellink.ToLinkError(err, ifName, targetNetNS.String()), "failed to delete interface: %v", ifName)
 	}
 	logger.Debugf("Interface %v successfully deleted in netNS %v", ifName, targetNetNS)
 	return nil
 }
 
-func move(ctx context.Context, conn *networkservice.Connection, vfRefCountMap map[string]int, vfRefCountMutex sync.Locker, isClient, isMoveBack bool) error {
+func move(ctx context.Context, conn *networkservice.Connection, refCounts *refCountStore, o *options, isClient, isMoveBack bool) error {
 	mech := kernel.ToMechanism(conn.GetMechanism())
 	logger := log.FromContext(ctx).WithField("inject", "move")
 	if mech == nil {
@@ -149,33 +155,35 @@ func move(ctx context.Context, conn *networkservice.Connection, vfRefCountMap ma
 		defer func() { _ = contNetNS.Close() }()
 	}
 
-	vfRefCountMutex.Lock()
-	defer vfRefCountMutex.Unlock()
+	refCounts.Lock()
+	defer refCounts.Unlock()
+	defer func() {
+		if saveErr := refCounts.save(); saveErr != nil {
+			logger.Warnf("Failed to persist VF reference counts (%v)", saveErr)
+		}
+	}()
 
-	vfRefKey := vfConfig.VFPCIAddress
-	if vfRefKey == "" {
-		vfRefKey = vfConfig.VFInterfaceName
-	}
+	vfRefKey := getVFRefKey(vfConfig)
 
 	ifName := mech.GetInterfaceName()
 	if !isMoveBack {
-		err = moveToContNetNS(vfConfig, vfRefCountMap, vfRefKey, ifName, hostNetNS, contNetNS, logger)
-		if err != nil {
-			// If we got an error, try to move back the vf to the host namespace
-			result := moveToHostNetNS(vfConfig, vfRefCountMap, vfRefKey, ifName, hostNetNS, contNetNS, logger)
-			if result != nil {
-				logger.Warnf("Failed to move interface %s to netNS %v, and to move it back to netNS %v", vfConfig.VFInterfaceName, hostNetNS, contNetNS)
+		var contIfName string
+		contIfName, err = moveToContNetNS(vfConfig, refCounts, o, vfRefKey, conn.GetId(), ifName, hostNetNS, contNetNS, logger)
+		if err == nil {
+			if o.collisionPolicy == CollisionPolicyAlternative && contIfName != ifName {
+				logger.Infof("Interface name %s is taken in netNS %v, %s is used instead", ifName, contNetNS, contIfName)
+				ifName = contIfName
+				mech.SetInterfaceName(ifName)
 			}
-		} else {
 			vfConfig.ContNetNS = contNetNS
 		}
 	} else {
-		err = moveToHostNetNS(vfConfig, vfRefCountMap, vfRefKey, ifName, hostNetNS, contNetNS, logger)
+		err = moveToHostNetNS(vfConfig, refCounts, vfRefKey, conn.GetId(), ifName, hostNetNS, contNetNS, logger)
 	}
 	if err != nil {
 		// link may not be available at this stage for cases like veth pair (might be deleted in previous chain element itself)
 		// or container would have killed already (example: due to OOM error or kubectl delete)
-		if strings.Contains(err.Error(), "Link not found") || strings.Contains(err.Error(), "bad file descriptor") {
+		if isLinkGone(err) {
 			logger.Warnf("Can not find interface, might be deleted already (%v)", err)
 			return nil
 		}
@@ -184,111 +192,256 @@ func move(ctx context.Context, conn *networkservice.Connection, vfRefCountMap ma
 	return nil
 }
 
-func moveToContNetNS(vfConfig *vfconfig.VFConfig, vfRefCountMap map[string]int, vfRefKey, ifName string, hostNetNS, contNetNS netns.NsHandle, logger log.Logger) (err error) {
-	if _, exists := vfRefCountMap[vfRefKey]; !exists {
-		vfRefCountMap[vfRefKey] = 1
-	} else {
-		vfRefCountMap[vfRefKey]++
-		logger.Debugf("Reference count increased to %d for vfRefKey %s", vfRefCountMap[vfRefKey], vfRefKey)
-		return nil
+// moveToContNetNS moves the VF to the contNetNS and returns the name it has got there, it differs from ifName only
+// with CollisionPolicyAlternative. The VF reference is counted only if the move succeeds, the completed steps are
+// undone otherwise.
+func moveToContNetNS(vfConfig *vfconfig.VFConfig, refCounts *refCountStore, o *options, vfRefKey, connID, ifName string,
+	hostNetNS, contNetNS netns.NsHandle, logger log.Logger) (string, error) {
+	if ref, ok := refCounts.refs[vfRefKey]; ok && len(ref.Connections) > 0 {
+		refCount := refCounts.inc(vfRefKey, connID, nil)
+		logger.Debugf("Reference count increased to %d for vfRefKey %s", refCount, vfRefKey)
+		// the references persisted by older versions have no interface name
+		if ref.IfName == "" {
+			ref.IfName = ifName
+		}
+		return ref.IfName, nil
+	}
+
+	var properties *linkProperties
+	if o.preserveProperties {
+		var err error
+		if properties, err = snapshotLinkProperties(vfConfig.VFInterfaceName); err != nil {
+			logger.Debugf("Interface %s properties are not preserved (%v)", vfConfig.VFInterfaceName, err)
+		}
 	}
+
+	// the RDMA device is visible only while it is in the forwarder netNS
+	rdmaDevice := getRDMADevice(vfConfig.VFPCIAddress)
+
+	tx := &moveTx{logger: logger}
+	contIfName, err := transferToContNetNS(tx, refCounts, vfConfig, o.collisionPolicy, connID, ifName, hostNetNS, contNetNS, logger)
+	if err == nil && rdmaDevice != "" {
+		err = tx.do(
+			func() error { return moveRDMADevice(rdmaDevice, hostNetNS, contNetNS, logger) },
+			func() error { return moveRDMADevice(rdmaDevice, contNetNS, hostNetNS, logger) },
+		)
+	}
+	if err != nil {
+		tx.rollback()
+		return contIfName, err
+	}
+
+	contNetNSInode, _ := nshandle.Inode(contNetNS)
+	refCounts.inc(vfRefKey, connID, &vfRef{
+		NetNSInode:      contNetNSInode,
+		VFInterfaceName: vfConfig.VFInterfaceName,
+		VFPCIAddress:    vfConfig.VFPCIAddress,
+		IfName:          contIfName,
+		Properties:      properties,
+		RDMADevice:      rdmaDevice,
+	})
+	refCounts.keepNetNS(vfRefKey, contNetNS)
+	return contIfName, nil
+}
+
+// transferToContNetNS does the steps of moving the VF to the contNetNS recording them in tx
+func transferToContNetNS(tx *moveTx, refCounts *refCountStore, vfConfig *vfconfig.VFConfig, policy CollisionPolicy, connID, ifName string,
+	hostNetNS, contNetNS netns.NsHandle, logger log.Logger) (contIfName string, err error) {
 	link, _ := kernellink.FindHostDevice("", ifName, contNetNS)
 	if link != nil {
-		if vfConfig != nil && vfConfig.VFInterfaceName != ifName {
-			hostLink, _ := kernellink.FindHostDevice(vfConfig.VFPCIAddress, vfConfig.VFInterfaceName, hostNetNS)
-			if hostLink != nil { // orphan link may remained from failed connection since no reference counter stored for it
-				removeOrphanLink(hostLink.GetName(), ifName, hostNetNS, contNetNS, logger)
-			} else { // do nothing
-				logger.Debugf("Device %s exist; link (%v) is already in the netNS %v", ifName, link.GetLink(), contNetNS)
-				return nil
-			}
-		} else { // do nothing
+		if vfConfig.VFInterfaceName == ifName { // do nothing
 			logger.Debugf("Device %s exist; (link %v, netNS %v)", ifName, link.GetLink(), contNetNS)
-			return nil
+			return ifName, nil
 		}
-	}
-	if vfConfig != nil && vfConfig.VFInterfaceName != ifName {
-		err = moveInterfaceToAnotherNamespace(vfConfig.VFInterfaceName, hostNetNS, contNetNS, logger)
-		if err == nil {
-			err = renameInterface(vfConfig.VFInterfaceName, ifName, contNetNS, logger)
-			if err == nil {
-				err = upInterface(ifName, contNetNS, logger)
+		hostLink, _ := kernellink.FindHostDevice(vfConfig.VFPCIAddress, vfConfig.VFInterfaceName, hostNetNS)
+		if hostLink == nil { // do nothing
+			logger.Debugf("Device %s exist; link (%v) is already in the netNS %v", ifName, link.GetLink(), contNetNS)
+			return ifName, nil
+		}
+		switch policy {
+		case CollisionPolicyFail:
+			return ifName, &NameCollisionError{Name: ifName, NetNS: contNetNS.String()}
+		case CollisionPolicyAlternative:
+			if ifName, err = getAlternativeName(contNetNS, ifName, connID); err != nil {
+				return ifName, err
+			}
+		default: // orphan link may remained from failed connection since no reference counter stored for it
+			if err = removeOrphanLink(refCounts, hostLink.GetName(), ifName, hostNetNS, contNetNS, logger); err != nil {
+				return ifName, err
 			}
 		}
-	} else {
-		err = moveInterfaceToAnotherNamespace(ifName, hostNetNS, contNetNS, logger)
 	}
-	return err
+
+	vfName := vfConfig.VFInterfaceName
+	if vfName == ifName {
+		return ifName, tx.do(
+			func() error { return MoveInterfaceToAnotherNamespace(ifName, hostNetNS, contNetNS, logger) },
+			func() error { return MoveInterfaceToAnotherNamespace(ifName, contNetNS, hostNetNS, logger) },
+		)
+	}
+	if err = tx.do(
+		func() error { return MoveInterfaceToAnotherNamespace(vfName, hostNetNS, contNetNS, logger) },
+		func() error { return MoveInterfaceToAnotherNamespace(vfName, contNetNS, hostNetNS, logger) },
+	); err != nil {
+		return ifName, err
+	}
+	if err = tx.do(
+		func() error { return RenameInterface(vfName, ifName, contNetNS, logger) },
+		func() error { return RenameInterface(ifName, vfName, contNetNS, logger) },
+	); err != nil {
+		return ifName, err
+	}
+	return ifName, tx.do(func() error { return UpInterface(ifName, contNetNS, logger) }, nil)
 }
 
-func moveToHostNetNS(vfConfig *vfconfig.VFConfig, vfRefCountMap map[string]int, vfRefKey, ifName string, hostNetNS, contNetNS netns.NsHandle, logger log.Logger) error {
-	var refCount int
-	if count, exists := vfRefCountMap[vfRefKey]; exists && count > 0 {
-		refCount = count - 1
-		vfRefCountMap[vfRefKey] = refCount
-	} else {
+// moveToHostNetNS moves the VF back to the hostNetNS once no connection uses it anymore. The connection reference is
+// dropped only if the move succeeds or the VF is gone, so a retried Close moves the VF back.
+func moveToHostNetNS(vfConfig *vfconfig.VFConfig, refCounts *refCountStore, vfRefKey, connID, ifName string, hostNetNS, contNetNS netns.NsHandle, logger log.Logger) error {
+	ref, ok := refCounts.lookup(vfRefKey, connID)
+	if !ok {
 		logger.Debugf("No reference for interface %s", vfRefKey)
 		return nil
 	}
+	if len(ref.Connections) > 1 {
+		refCount, _ := refCounts.dec(vfRefKey, connID)
+		logger.Debugf("Reference count decreased to %d for vfRefKey %s", refCount, vfRefKey)
+		return nil
+	}
 
-	if refCount == 0 {
-		delete(vfRefCountMap, vfRefKey)
-		if vfConfig != nil && vfConfig.VFInterfaceName != ifName {
-			link, _ := kernellink.FindHostDevice(vfConfig.VFPCIAddress, vfConfig.VFInterfaceName, hostNetNS)
-			if link != nil {
-				linkName := link.GetName()
-				logger.Debugf("Device %s found in netNS %v", linkName, hostNetNS)
-				if linkName != vfConfig.VFInterfaceName {
-					if err := netlink.LinkSetName(link.GetLink(), vfConfig.VFInterfaceName); err != nil {
-						return errors.Wrapf(err, "failed to rename interface from %s to %s: %v", linkName, vfConfig.VFInterfaceName, err)
-					}
-					logger.Debugf("Interface renamed %s -> %s in netNS %v", linkName, vfConfig.VFInterfaceName, hostNetNS)
-				}
-				return nil
-			}
-			err := renameInterface(ifName, vfConfig.VFInterfaceName, contNetNS, logger)
-			if err == nil {
-				err = moveInterfaceToAnotherNamespace(vfConfig.VFInterfaceName, contNetNS, hostNetNS, logger)
-			}
-			return err
+	// the name recorded for the connection, the mechanism may still have the requested one with
+	// CollisionPolicyAlternative
+	if ref.IfName != "" {
+		ifName = ref.IfName
+	}
+	if ref.RDMADevice != "" {
+		// the RDMA device is returned to the forwarder netNS by the kernel if the pod netNS is gone
+		if err := moveRDMADevice(ref.RDMADevice, contNetNS, hostNetNS, logger); err != nil {
+			logger.Warnf("Failed to move RDMA device %s back to netNS %v (%v)", ref.RDMADevice, hostNetNS, err)
+		}
+	}
+	if err := transferToHostNetNS(vfConfig, ifName, hostNetNS, contNetNS, logger); err != nil {
+		if isLinkGone(err) {
+			refCounts.dec(vfRefKey, connID)
 		}
-		link, _ := kernellink.FindHostDevice("", ifName, hostNetNS)
+		return err
+	}
+	refCounts.dec(vfRefKey, connID)
+	if ref.Properties != nil {
+		if err := restoreLinkProperties(vfConfig.VFInterfaceName, ref.Properties, logger); err != nil {
+			logger.Warnf("Failed to restore interface %s properties (%v)", vfConfig.VFInterfaceName, err)
+		}
+	}
+	return nil
+}
+
+func transferToHostNetNS(vfConfig *vfconfig.VFConfig, ifName string, hostNetNS, contNetNS netns.NsHandle, logger log.Logger) error {
+	if vfConfig.VFInterfaceName != ifName {
+		link, _ := kernellink.FindHostDevice(vfConfig.VFPCIAddress, vfConfig.VFInterfaceName, hostNetNS)
 		if link != nil {
-			logger.Debugf("Interface %s found in netNS %v", ifName, hostNetNS)
+			linkName := link.GetName()
+			logger.Debugf("Device %s found in netNS %v", linkName, hostNetNS)
+			if linkName != vfConfig.VFInterfaceName {
+				if err := netlink.LinkSetName(link.GetLink(), vfConfig.VFInterfaceName); err != nil {
+					return errors.Wrapf(kernellink.ToLinkError(err, vfConfig.VFInterfaceName, hostNetNS.String()), "failed to rename interface from %s to %s", linkName, vfConfig.VFInterfaceName)
+				}
+				logger.Debugf("Interface renamed %s -> %s in netNS %v", linkName, vfConfig.VFInterfaceName, hostNetNS)
+			}
 			return nil
 		}
-		return moveInterfaceToAnotherNamespace(ifName, contNetNS, hostNetNS, logger)
+		err := RenameInterface(ifName, vfConfig.VFInterfaceName, contNetNS, logger)
+		if err == nil {
+			err = MoveInterfaceToAnotherNamespace(vfConfig.VFInterfaceName, contNetNS, hostNetNS, logger)
+		}
+		return err
 	}
-	return nil
+	link, _ := kernellink.FindHostDevice("", ifName, hostNetNS)
+	if link != nil {
+		logger.Debugf("Interface %s found in netNS %v", ifName, hostNetNS)
+		return nil
+	}
+	return MoveInterfaceToAnotherNamespace(ifName, contNetNS, hostNetNS, logger)
 }
 
-func removeOrphanLink(hostIfName, ifName string, hostNetNS, contNetNS netns.NsHandle, logger log.Logger) {
+// removeOrphanLink renames the orphan ifName link in the contNetNS to a temporary name and takes care of it the same
+// way the orphan reaper does. It fails only if no temporary name is free, the orphan reaper retries the other failures.
+func removeOrphanLink(refCounts *refCountStore, hostIfName, ifName string, hostNetNS, contNetNS netns.NsHandle, logger log.Logger) error {
 	logger.Debugf("Orphan interface %s found on netNS %s and interface %s still in host netNS %s",
 		ifName, contNetNS, hostIfName, hostNetNS)
-	tmpIfName := getTempName(contNetNS, hostIfName)
-	if err := renameInterface(ifName, tmpIfName, contNetNS, logger); err != nil {
+	tmpIfName, err := getTempName(hostNetNS, contNetNS, hostIfName)
+	if err != nil {
+		return err
+	}
+	// the temporary name is recorded so that the orphan reaper takes care of the link if it can't be done now
+	contNetNSInode, _ := nshandle.Inode(contNetNS)
+	refCounts.tmpLinks[tmpIfName] = &tmpLink{
+		NetNSInode:      contNetNSInode,
+		VFInterfaceName: refCounts.vfInterfaceName(contNetNSInode, ifName),
+	}
+	if err = RenameInterface(ifName, tmpIfName, contNetNS, logger); err != nil {
 		logger.Warnf("Failed to rename orphan interface %s (%v)", ifName, err)
-		tmpIfName = ifName
+		delete(refCounts.tmpLinks, tmpIfName)
+		return nil
 	}
-	orphanIntNetNS := hostNetNS
-	if err := moveInterfaceToAnotherNamespace(tmpIfName, contNetNS, hostNetNS, logger); err != nil {
-		logger.Warnf("Failed to move orphan interface %s back to host netNS %v (%v)", tmpIfName, hostNetNS, err)
-		orphanIntNetNS = contNetNS
+	if reaped := restoreTmpLink(refCounts, tmpIfName, hostNetNS, contNetNS, logger); reaped != nil && reaped.Err != nil {
+		logger.Warnf("Orphan interface %s: %s failed (%v)", tmpIfName, reaped.Action, reaped.Err)
 	}
-	if err := deleteInterface(tmpIfName, orphanIntNetNS, logger); err != nil {
-		logger.Warnf("Failed to delete interface %s from netNS %v (%v)", tmpIfName, orphanIntNetNS, err)
+	return nil
+}
+
+// isLinkGone tells whether the error is caused by the link or its netNS being gone, e.g. the veth pair deleted by a
+// previous chain element or the container killed already (OOM error or kubectl delete)
+func isLinkGone(err error) bool {
+	var linkNotFound *LinkNotFoundError
+	var netNSGone *NetNSGoneError
+	return errors.As(err, &linkNotFound) || errors.As(err, &netNSGone)
+}
+
+// maxNameAttempts limits the search for a free temporary or alternative interface name
+const maxNameAttempts = 256
+
+// getTempName returns a "tmp-<hash>" name which is free both in the hostNetNS and in the contNetNS, the name fits
+// into kernel.LinuxIfMaxLength
+func getTempName(hostNetNS, contNetNS netns.NsHandle, ifName string) (string, error) {
+	inode, _ := nshandle.Inode(contNetNS)
+	for i := 0; i < maxNameAttempts; i++ {
+		name := fmt.Sprintf("%s%08x", tmpIfNamePrefix, nameHash(fmt.Sprintf("%d/%s", inode, ifName), i))
+		if !linkExists(contNetNS, name) && !linkExists(hostNetNS, name) {
+			return name, nil
+		}
 	}
+	return "", errors.Wrapf(&NameCollisionError{Name: tmpIfNamePrefix + "*", NetNS: contNetNS.String()},
+		"failed to find a free temporary name for interface %s", ifName)
 }
 
-func getTempName(netNS netns.NsHandle, ifName string) string {
-	suffix := ifName[strings.IndexByte(ifName, '-')+1:]
-	var s unix.Stat_t
-	if err := unix.Fstat(int(netNS), &s); err == nil {
-		suffix = fmt.Sprintf("%d", s.Ino)
+// getAlternativeName returns a name derived from ifName and connID which is free in the netNS, the same name is
+// returned for the same connection as long as it is free
+func getAlternativeName(netNS netns.NsHandle, ifName, connID string) (string, error) {
+	const suffixLen = 5 // "-xxxx"
+	base := ifName
+	if len(base) > kernel.LinuxIfMaxLength-suffixLen {
+		base = base[:kernel.LinuxIfMaxLength-suffixLen]
+	}
+	for i := 0; i < maxNameAttempts; i++ {
+		name := fmt.Sprintf("%s-%04x", base, nameHash(connID, i)&0xffff)
+		if !linkExists(netNS, name) {
+			return name, nil
+		}
 	}
-	name := fmt.Sprintf("tmp-%d-%s", int(netNS), suffix)
-	if len(name) > kernel.LinuxIfMaxLength {
-		name = name[:kernel.LinuxIfMaxLength]
+	return ifName, &NameCollisionError{Name: ifName, NetNS: netNS.String()}
+}
+
+func nameHash(key string, attempt int) uint32 {
+	h := fnv.New32a()
+	_, _ = fmt.Fprintf(h, "%s/%d", key, attempt)
+	return h.Sum32()
+}
+
+func linkExists(netNS netns.NsHandle, name string) bool {
+	handle, err := netlink.NewHandleAt(netNS)
+	if err != nil {
+		return false
 	}
-	return name
+	defer handle.Close()
+	_, err = handle.LinkByName(name)
+	return err == nil
 }
@@ -0,0 +1,36 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package inject
+
+import (
+	kernellink "github.com/ljkiraly/sdk-kernel/pkg/kernel"
+)
+
+// The errors returned by inject and by the exported interface helpers, the callers can branch on them with errors.As
+type (
+	// LinkNotFoundError is returned when the interface is not found
+	LinkNotFoundError = kernellink.LinkNotFoundError
+	// NetNSGoneError is returned when the net NS is closed or doesn't exist anymore
+	NetNSGoneError = kernellink.NetNSGoneError
+	// NameCollisionError is returned when an interface with the desired name already exists in the net NS
+	NameCollisionError = kernellink.NameCollisionError
+	// PermissionError is returned when the interface operation is not permitted
+	PermissionError = kernellink.PermissionError
+)
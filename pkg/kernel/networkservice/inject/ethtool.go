@@ -0,0 +1,94 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package inject
+
+import (
+	"runtime"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// ethtoolOffloads are the offloads which can be read and set with the legacy ETHTOOL_G*/ETHTOOL_S* commands
+var ethtoolOffloads = map[string]struct{ get, set uint32 }{
+	"rx-checksum":                  {get: 0x14, set: 0x15},
+	"tx-checksum":                  {get: 0x16, set: 0x17},
+	"scatter-gather":               {get: 0x18, set: 0x19},
+	"tcp-segmentation-offload":     {get: 0x1e, set: 0x1f},
+	"generic-segmentation-offload": {get: 0x23, set: 0x24},
+	"generic-receive-offload":      {get: 0x2b, set: 0x2c},
+}
+
+// ethtoolValue is struct ethtool_value
+type ethtoolValue struct {
+	cmd  uint32
+	data uint32
+}
+
+// ethtoolIfreq is struct ifreq with the ifr_data member
+type ethtoolIfreq struct {
+	name [unix.IFNAMSIZ]byte
+	data unsafe.Pointer
+	_    [16]byte
+}
+
+func ethtoolIoctl(ifName string, value *ethtoolValue) error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return errors.Wrap(err, "failed to open ethtool socket")
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	ifr := ethtoolIfreq{data: unsafe.Pointer(value)}
+	copy(ifr.name[:unix.IFNAMSIZ-1], ifName)
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), unix.SIOCETHTOOL, uintptr(unsafe.Pointer(&ifr)))
+	runtime.KeepAlive(&ifr)
+	if errno != 0 {
+		return errors.Wrapf(errno, "ethtool command 0x%x failed on %s", value.cmd, ifName)
+	}
+	return nil
+}
+
+// getOffloads returns the offloads supported by the ifName interface in the current netNS
+func getOffloads(ifName string) map[string]bool {
+	offloads := make(map[string]bool)
+	for name, cmds := range ethtoolOffloads {
+		value := &ethtoolValue{cmd: cmds.get}
+		if err := ethtoolIoctl(ifName, value); err != nil {
+			continue
+		}
+		offloads[name] = value.data != 0
+	}
+	return offloads
+}
+
+// setOffload sets the offload of the ifName interface in the current netNS
+func setOffload(ifName, name string, enabled bool) error {
+	cmds, ok := ethtoolOffloads[name]
+	if !ok {
+		return errors.Errorf("unknown offload %s", name)
+	}
+	value := &ethtoolValue{cmd: cmds.set}
+	if enabled {
+		value.data = 1
+	}
+	return ethtoolIoctl(ifName, value)
+}
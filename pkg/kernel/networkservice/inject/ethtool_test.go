@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build perm
+// +build perm
+
+package inject
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+func TestEthtool_OffloadRoundTripPerm(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	baseHandle, err := netns.Get()
+	require.NoError(t, err)
+	defer func() {
+		_ = netns.Set(baseHandle)
+		_ = baseHandle.Close()
+	}()
+
+	newHandle, err := netns.New()
+	require.NoError(t, err)
+	defer func() { _ = newHandle.Close() }()
+
+	const ifName = "nsm-eth"
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  ifName + "-peer",
+	}))
+
+	const offload = "tx-checksum"
+	enabled, ok := getOffloads(ifName)[offload]
+	require.True(t, ok, "%s is not reported", offload)
+
+	require.NoError(t, setOffload(ifName, offload, !enabled))
+	require.Equal(t, !enabled, getOffloads(ifName)[offload])
+
+	require.NoError(t, setOffload(ifName, offload, enabled))
+	require.Equal(t, enabled, getOffloads(ifName)[offload])
+
+	require.Error(t, setOffload(ifName, "unknown-offload", true))
+}
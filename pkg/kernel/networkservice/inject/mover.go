@@ -0,0 +1,103 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package inject
+
+import (
+	"context"
+
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+)
+
+// Mover moves VFs to and from the pod netNSes the same way the inject chain elements do: the VF references are
+// counted and persisted, the collision policy is applied and a failed move is rolled back. A Mover created with the
+// same state file as an inject chain element shares the references with it.
+type Mover struct {
+	refCounts *refCountStore
+	options   *options
+}
+
+// NewMover - returns a new Mover configured with the inject options
+func NewMover(opts ...Option) *Mover {
+	o := newOptions(opts...)
+	return &Mover{
+		refCounts: newRefCountStoreWithOptions(o),
+		options:   o,
+	}
+}
+
+// MoveToContNetNS moves the VF to the contNetNS for the connection and returns the name it has got there, the name
+// differs from ifName only with CollisionPolicyAlternative. contNetNS is not stored in vfConfig.ContNetNS, so the
+// caller may close it: the Mover keeps its own handle of the pod netNS until the VF is moved back.
+func (m *Mover) MoveToContNetNS(ctx context.Context, vfConfig *vfconfig.VFConfig, connID, ifName string,
+	hostNetNS, contNetNS netns.NsHandle) (string, error) {
+	logger := log.FromContext(ctx).WithField("inject", "MoveToContNetNS")
+
+	m.refCounts.Lock()
+	defer m.refCounts.Unlock()
+	defer m.save(logger)
+
+	return moveToContNetNS(vfConfig, m.refCounts, m.options, getVFRefKey(vfConfig), connID, ifName,
+		hostNetNS, contNetNS, logger)
+}
+
+// MoveToHostNetNS moves the VF known as ifName in the contNetNS back to the hostNetNS with its original name once
+// no connection uses it anymore. The pod netNS kept for the VF is used if contNetNS is not open.
+func (m *Mover) MoveToHostNetNS(ctx context.Context, vfConfig *vfconfig.VFConfig, connID, ifName string,
+	hostNetNS, contNetNS netns.NsHandle) error {
+	logger := log.FromContext(ctx).WithField("inject", "MoveToHostNetNS")
+
+	m.refCounts.Lock()
+	defer m.refCounts.Unlock()
+	defer m.save(logger)
+
+	vfRefKey := getVFRefKey(vfConfig)
+	if !contNetNS.IsOpen() {
+		// the kept handle is closed when the VF is forgotten, so a duplicate is used
+		if kept, closeNetNS, err := m.refCounts.openNetNS(vfRefKey); err == nil {
+			fd, dupErr := unix.Dup(int(kept))
+			closeNetNS()
+			if dupErr == nil {
+				contNetNS = netns.NsHandle(fd)
+				defer func() { _ = contNetNS.Close() }()
+			}
+		}
+	}
+
+	return moveToHostNetNS(vfConfig, m.refCounts, vfRefKey, connID, ifName, hostNetNS, contNetNS, logger)
+}
+
+func (m *Mover) save(logger log.Logger) {
+	if err := m.refCounts.save(); err != nil {
+		logger.Warnf("Failed to persist VF reference counts (%v)", err)
+	}
+}
+
+// getVFRefKey returns the key the VF references are counted with
+func getVFRefKey(vfConfig *vfconfig.VFConfig) string {
+	if vfConfig.VFPCIAddress != "" {
+		return vfConfig.VFPCIAddress
+	}
+	return vfConfig.VFInterfaceName
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package inject
+
+import (
+	"context"
+	"time"
+)
+
+// CollisionPolicy tells what to do when the interface name requested in the mechanism is already taken in
+// the target netNS by an interface other than the VF
+type CollisionPolicy int
+
+const (
+	// CollisionPolicyReplaceOrphan - the interface is considered an orphan left by a failed connection, it is moved
+	// out of the target netNS and deleted. This is the default.
+	CollisionPolicyReplaceOrphan CollisionPolicy = iota
+	// CollisionPolicyFail - the Request fails with NameCollisionError
+	CollisionPolicyFail
+	// CollisionPolicyAlternative - a deterministic alternative name is picked for the VF and it is set to the mechanism
+	CollisionPolicyAlternative
+)
+
+type options struct {
+	stateFile          string
+	collisionPolicy    CollisionPolicy
+	preserveProperties bool
+	reaperCtx          context.Context
+	reaperOptions      *reaperOptions
+	watcherCtx         context.Context
+	watchInterval      time.Duration
+}
+
+// Option is an option pattern for NewClient, NewServer
+type Option func(o *options)
+
+// WithStateFile - sets the file the VF reference counts and the VF to netNS ownership are persisted to,
+// so they survive a forwarder restart. The state is kept in memory only if not set. The client and the server
+// created with the same state file share the references.
+func WithStateFile(path string) Option {
+	return func(o *options) {
+		o.stateFile = path
+	}
+}
+
+// WithCollisionPolicy - sets what to do when the requested interface name is already taken in the target netNS,
+// CollisionPolicyReplaceOrphan by default
+func WithCollisionPolicy(policy CollisionPolicy) Option {
+	return func(o *options) {
+		o.collisionPolicy = policy
+	}
+}
+
+// WithPropertyPreservation - snapshots the MTU, alias, altnames, admin state and offloads of the VF before it is moved
+// to the pod and restores them after it is moved back, so the forwarder side view of the VF is the same
+func WithPropertyPreservation() Option {
+	return func(o *options) {
+		o.preserveProperties = true
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package inject
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+
+	kernellink "github.com/ljkiraly/sdk-kernel/pkg/kernel"
+)
+
+// linkProperties are the forwarder side properties of the VF restored after it is moved back
+type linkProperties struct {
+	MTU      int             `json:"mtu,omitempty"`
+	Alias    string          `json:"alias,omitempty"`
+	AltNames []string        `json:"altNames,omitempty"`
+	Up       bool            `json:"up,omitempty"`
+	Offloads map[string]bool `json:"offloads,omitempty"`
+}
+
+// snapshotLinkProperties reads the properties of the ifName interface in the current netNS
+func snapshotLinkProperties(ifName string) (*linkProperties, error) {
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return nil, errors.Wrapf(kernellink.ToLinkError(err, ifName, ""), "failed to get net interface: %v", ifName)
+	}
+	attrs := link.Attrs()
+	return &linkProperties{
+		MTU:      attrs.MTU,
+		Alias:    attrs.Alias,
+		AltNames: attrs.AltNames,
+		Up:       attrs.Flags&net.FlagUp != 0,
+		Offloads: getOffloads(ifName),
+	}, nil
+}
+
+// restoreLinkProperties sets the properties of the ifName interface in the current netNS, all of them are tried
+// and the first failure is returned
+func restoreLinkProperties(ifName string, properties *linkProperties, logger log.Logger) error {
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return errors.Wrapf(kernellink.ToLinkError(err, ifName, ""), "failed to get net interface: %v", ifName)
+	}
+	attrs := link.Attrs()
+
+	var errs []error
+	if properties.MTU != 0 && attrs.MTU != properties.MTU {
+		errs = append(errs, errors.Wrapf(netlink.LinkSetMTU(link, properties.MTU), "failed to set MTU %d", properties.MTU))
+	}
+	if attrs.Alias != properties.Alias {
+		errs = append(errs, errors.Wrapf(netlink.LinkSetAlias(link, properties.Alias), "failed to set alias %s", properties.Alias))
+	}
+	for _, altName := range properties.AltNames {
+		if !containsString(attrs.AltNames, altName) {
+			errs = append(errs, errors.Wrapf(netlink.LinkAddAltName(link, altName), "failed to add altname %s", altName))
+		}
+	}
+	current := getOffloads(ifName)
+	for name, enabled := range properties.Offloads {
+		if cur, ok := current[name]; ok && cur != enabled {
+			errs = append(errs, setOffload(ifName, name, enabled))
+		}
+	}
+	if properties.Up {
+		errs = append(errs, errors.Wrap(netlink.LinkSetUp(link), "failed to set up"))
+	} else {
+		errs = append(errs, errors.Wrap(netlink.LinkSetDown(link), "failed to set down"))
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return errors.Wrapf(err, "failed to restore interface %s properties", ifName)
+		}
+	}
+	logger.Debugf("Interface %s properties restored", ifName)
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
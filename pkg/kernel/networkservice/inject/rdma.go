@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package inject
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+)
+
+const rdmaNetNSModeExclusive = "exclusive"
+
+// getRDMADevice returns the name of the RDMA device of the VF with the PCI address, it is empty if the RDMA
+// subsystem is not in exclusive netNS mode or the VF has no RDMA device in the current netNS
+func getRDMADevice(pciAddress string) string {
+	if pciAddress == "" {
+		return ""
+	}
+	if mode, err := netlink.RdmaSystemGetNetnsMode(); err != nil || mode != rdmaNetNSModeExclusive {
+		return ""
+	}
+	entries, err := os.ReadDir(filepath.Join("/sys/bus/pci/devices", pciAddress, "infiniband"))
+	if err != nil || len(entries) == 0 {
+		return ""
+	}
+	return entries[0].Name()
+}
+
+// moveRDMADevice moves the rdmaDevice RDMA device from the fromNetNS to the toNetNS
+func moveRDMADevice(rdmaDevice string, fromNetNS, toNetNS netns.NsHandle, logger log.Logger) error {
+	handle, err := netlink.NewHandleAt(fromNetNS)
+	if err != nil {
+		return errors.Wrap(err, "failed to create netlink fromNetNS handle")
+	}
+	defer handle.Close()
+
+	link, err := handle.RdmaLinkByName(rdmaDevice)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get RDMA device: %v", rdmaDevice)
+	}
+
+	if err = handle.RdmaLinkSetNsFd(link, uint32(toNetNS)); err != nil {
+		return errors.Wrapf(err, "failed to move RDMA device to net NS: %v %v", rdmaDevice, toNetNS)
+	}
+	logger.Debugf("RDMA device %v moved from netNS %v into the netNS %v", rdmaDevice, fromNetNS, toNetNS)
+	return nil
+}
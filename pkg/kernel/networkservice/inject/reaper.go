@@ -0,0 +1,406 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package inject
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+
+	kernellink "github.com/ljkiraly/sdk-kernel/pkg/kernel"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+const (
+	tmpIfNamePrefix     = "tmp-"
+	defaultReapInterval = time.Minute
+)
+
+// ReapAction is an action the orphan reaper has taken on a link
+type ReapAction string
+
+const (
+	// ReapActionDeleted - the leftover link has been deleted
+	ReapActionDeleted ReapAction = "deleted"
+	// ReapActionMovedBack - the VF has been moved back to the forwarder netNS with its original name
+	ReapActionMovedBack ReapAction = "moved back"
+)
+
+// ReapedLink describes a link the orphan reaper has taken care of
+type ReapedLink struct {
+	// Name is the name of the link when found
+	Name string
+	// NetNSInode is the inode of the netNS the link was found in
+	NetNSInode uint64
+	// VFInterfaceName is the original VF name, set for ReapActionMovedBack
+	VFInterfaceName string
+	// Action is the action taken on the link
+	Action ReapAction
+	// Err is set if the action has failed
+	Err error
+}
+
+type reaperOptions struct {
+	interval          time.Duration
+	connectionTimeout time.Duration
+	isAlive           func(connID string) bool
+	onReap            func(reaped *ReapedLink)
+}
+
+// ReaperOption is an option pattern for WithOrphanReaper
+type ReaperOption func(o *reaperOptions)
+
+// WithReapInterval - sets how often the orphan reaper scans the netNSes, 1m by default
+func WithReapInterval(interval time.Duration) ReaperOption {
+	return func(o *reaperOptions) {
+		o.interval = interval
+	}
+}
+
+// WithConnectionTimeout - sets the time after the last Request a connection is considered gone. It is not set by
+// default and it is not used if WithConnectionCheck is set.
+func WithConnectionTimeout(timeout time.Duration) ReaperOption {
+	return func(o *reaperOptions) {
+		o.connectionTimeout = timeout
+	}
+}
+
+// WithConnectionCheck - sets the function telling whether the connection still exists. Without it and without
+// WithConnectionTimeout every connection is considered alive.
+func WithConnectionCheck(isAlive func(connID string) bool) ReaperOption {
+	return func(o *reaperOptions) {
+		o.isAlive = isAlive
+	}
+}
+
+// WithReapCallback - sets the function called for every link the orphan reaper has taken care of
+func WithReapCallback(onReap func(reaped *ReapedLink)) ReaperOption {
+	return func(o *reaperOptions) {
+		o.onReap = onReap
+	}
+}
+
+// WithOrphanReaper - runs a background reaper until ctx is done. It periodically takes care of the temporary
+// "tmp-*" links left in the forwarder netNS and in the known pod netNSes: the VFs are moved back to the forwarder
+// netNS with their original names, the other links are deleted. It also moves the VFs of the connections which no
+// longer exist back to the forwarder netNS with their original names.
+func WithOrphanReaper(ctx context.Context, opts ...ReaperOption) Option {
+	return func(o *options) {
+		ro := &reaperOptions{
+			interval: defaultReapInterval,
+		}
+		for _, opt := range opts {
+			opt(ro)
+		}
+		o.reaperCtx = ctx
+		o.reaperOptions = ro
+	}
+}
+
+type orphanReaper struct {
+	refCounts *refCountStore
+	options   *reaperOptions
+}
+
+func (r *orphanReaper) run(ctx context.Context) {
+	ticker := time.NewTicker(r.options.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.reap(ctx)
+		}
+	}
+}
+
+func (r *orphanReaper) reap(ctx context.Context) {
+	logger := log.FromContext(ctx).WithField("inject", "orphanReaper")
+
+	hostNetNS, err := nshandle.Current()
+	if err != nil {
+		logger.Errorf("Failed to get forwarder netNS: %v", err)
+		return
+	}
+	defer func() { _ = hostNetNS.Close() }()
+
+	r.refCounts.Lock()
+	defer r.refCounts.Unlock()
+
+	r.reapTmpLinks(hostNetNS, logger)
+	r.reapVFs(hostNetNS, logger)
+
+	if saveErr := r.refCounts.save(); saveErr != nil {
+		logger.Warnf("Failed to persist VF reference counts (%v)", saveErr)
+	}
+}
+
+func (r *orphanReaper) isAlive(connID string) bool {
+	if r.options.isAlive != nil {
+		return r.options.isAlive(connID)
+	}
+	// without a connection check nothing is known about the connection, so it is considered alive
+	if r.options.connectionTimeout <= 0 {
+		return true
+	}
+	lastSeen, ok := r.refCounts.lastSeen[connID]
+	return ok && time.Since(lastSeen) < r.options.connectionTimeout
+}
+
+// reapVFs forgets the connections which no longer exist and moves the VFs nobody uses back to the forwarder netNS
+func (r *orphanReaper) reapVFs(hostNetNS netns.NsHandle, logger log.Logger) {
+	for key, ref := range r.refCounts.refs {
+		var alive []string
+		for _, connID := range ref.Connections {
+			if r.isAlive(connID) {
+				alive = append(alive, connID)
+				continue
+			}
+			logger.Infof("Connection %s using VF %s no longer exists", connID, key)
+			delete(r.refCounts.lastSeen, connID)
+		}
+		ref.Connections = alive
+		if len(alive) > 0 {
+			continue
+		}
+
+		name, err := reclaimVF(r.refCounts, key, hostNetNS, logger)
+		// the VF is kept to be reclaimed by the next pass if it fails
+		if err == nil {
+			r.refCounts.forget(key)
+		}
+		r.report(&ReapedLink{
+			Name:            name,
+			NetNSInode:      ref.NetNSInode,
+			VFInterfaceName: ref.VFInterfaceName,
+			Action:          ReapActionMovedBack,
+			Err:             err,
+		}, logger)
+	}
+}
+
+// reapTmpLinks takes care of the "tmp-*" links in the forwarder netNS and in the known pod netNSes. The links left
+// without a record, e.g. by a crash before the record was written, are taken care of as well.
+func (r *orphanReaper) reapTmpLinks(hostNetNS netns.NsHandle, logger log.Logger) {
+	podNetNSes, closeNetNSes := r.podNetNSes()
+	defer closeNetNSes()
+
+	// the pod netNS of the link, the links found in the forwarder netNS have none
+	links := make(map[string]netns.NsHandle)
+	for _, name := range tmpLinkNames(hostNetNS, logger) {
+		links[name] = netns.None()
+	}
+	for _, contNetNS := range podNetNSes {
+		for _, name := range tmpLinkNames(contNetNS, logger) {
+			links[name] = contNetNS
+		}
+	}
+	// the recorded links not found are forgotten by restoreTmpLink
+	for name, tmp := range r.refCounts.tmpLinks {
+		if _, ok := links[name]; !ok {
+			links[name] = netns.None()
+			if contNetNS, ok := podNetNSes[tmp.NetNSInode]; ok {
+				links[name] = contNetNS
+			}
+		}
+	}
+
+	for name, contNetNS := range links {
+		if reaped := restoreTmpLink(r.refCounts, name, hostNetNS, contNetNS, logger); reaped != nil {
+			r.report(reaped, logger)
+		}
+	}
+}
+
+// podNetNSes returns the known pod netNSes keyed by inode, the returned function closes the ones opened here
+func (r *orphanReaper) podNetNSes() (podNetNSes map[uint64]netns.NsHandle, closeFunc func()) {
+	podNetNSes = make(map[uint64]netns.NsHandle)
+	for key, handle := range r.refCounts.netNSHandles {
+		if ref, ok := r.refCounts.refs[key]; ok && ref.NetNSInode != 0 {
+			podNetNSes[ref.NetNSInode] = handle
+		}
+	}
+
+	var opened []netns.NsHandle
+	open := func(inode uint64) {
+		if _, ok := podNetNSes[inode]; ok || inode == 0 {
+			return
+		}
+		handle, err := nshandle.FromInode(inode)
+		if err != nil {
+			return
+		}
+		podNetNSes[inode] = handle
+		opened = append(opened, handle)
+	}
+	for _, ref := range r.refCounts.refs {
+		open(ref.NetNSInode)
+	}
+	for _, tmp := range r.refCounts.tmpLinks {
+		open(tmp.NetNSInode)
+	}
+
+	return podNetNSes, func() {
+		for _, handle := range opened {
+			_ = handle.Close()
+		}
+	}
+}
+
+// tmpLinkNames returns the names of the "tmp-*" links in the netNS
+func tmpLinkNames(netNS netns.NsHandle, logger log.Logger) []string {
+	handle, err := netlink.NewHandleAt(netNS)
+	if err != nil {
+		logger.Warnf("Failed to create netlink handle for netNS %v (%v)", netNS, err)
+		return nil
+	}
+	defer handle.Close()
+
+	links, err := handle.LinkList()
+	if err != nil {
+		logger.Warnf("Failed to list links in netNS %v (%v)", netNS, err)
+		return nil
+	}
+	var names []string
+	for _, link := range links {
+		if name := link.Attrs().Name; strings.HasPrefix(name, tmpIfNamePrefix) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (r *orphanReaper) report(reaped *ReapedLink, logger log.Logger) {
+	if reaped.Err != nil {
+		logger.Errorf("Orphan link %s in netNS %d: %s failed: %v", reaped.Name, reaped.NetNSInode, reaped.Action, reaped.Err)
+	} else {
+		logger.Infof("Orphan link %s in netNS %d: %s", reaped.Name, reaped.NetNSInode, reaped.Action)
+	}
+	if r.options.onReap != nil {
+		r.options.onReap(reaped)
+	}
+}
+
+// reclaimVF moves the VF back to the forwarder netNS with its original name and returns the name the VF was found with
+func reclaimVF(refCounts *refCountStore, key string, hostNetNS netns.NsHandle, logger log.Logger) (string, error) {
+	ref := refCounts.refs[key]
+
+	name, err := transferVFToHostNetNS(refCounts, key, ref, hostNetNS, logger)
+	if err != nil || ref.Properties == nil {
+		return name, err
+	}
+	return name, restoreLinkProperties(ref.VFInterfaceName, ref.Properties, logger)
+}
+
+func transferVFToHostNetNS(refCounts *refCountStore, key string, ref *vfRef, hostNetNS netns.NsHandle, logger log.Logger) (string, error) {
+	// the VF is already in the forwarder netNS if the pod netNS is gone
+	if link, _ := kernellink.FindHostDevice(ref.VFPCIAddress, ref.VFInterfaceName, hostNetNS); link != nil {
+		return link.GetName(), link.SetName(ref.VFInterfaceName)
+	}
+
+	contNetNS, closeNetNS, err := refCounts.openNetNS(key)
+	if err != nil {
+		return ref.IfName, err
+	}
+	defer closeNetNS()
+
+	if ref.RDMADevice != "" {
+		if rdmaErr := moveRDMADevice(ref.RDMADevice, contNetNS, hostNetNS, logger); rdmaErr != nil {
+			logger.Warnf("Failed to move RDMA device %s back to netNS %v (%v)", ref.RDMADevice, hostNetNS, rdmaErr)
+		}
+	}
+
+	link, err := kernellink.FindHostDevice(ref.VFPCIAddress, ref.IfName, contNetNS)
+	if err != nil {
+		return ref.IfName, err
+	}
+	name := link.GetName()
+	if name != ref.VFInterfaceName {
+		if err = RenameInterface(name, ref.VFInterfaceName, contNetNS, logger); err != nil {
+			return name, err
+		}
+	}
+	return name, MoveInterfaceToAnotherNamespace(ref.VFInterfaceName, contNetNS, hostNetNS, logger)
+}
+
+// restoreTmpLink takes care of the link with the temporary name: a VF is moved back to the forwarder netNS
+// with its original name, any other link is deleted. The record is forgotten once the link is taken care of or gone.
+func restoreTmpLink(refCounts *refCountStore, name string, hostNetNS, contNetNS netns.NsHandle, logger log.Logger) *ReapedLink {
+	netNS := hostNetNS
+	link, err := findLink(hostNetNS, name)
+	if err != nil && contNetNS.IsOpen() {
+		netNS = contNetNS
+		link, err = findLink(contNetNS, name)
+	}
+	if err != nil {
+		logger.Debugf("Temporary link %s is gone (%v)", name, err)
+		delete(refCounts.tmpLinks, name)
+		return nil
+	}
+
+	// the link is not recorded if the forwarder has stopped before writing the record
+	tmp, ok := refCounts.tmpLinks[name]
+	if !ok {
+		tmp = &tmpLink{}
+		tmp.NetNSInode, _ = nshandle.Inode(netNS)
+	}
+	reaped := &ReapedLink{Name: name, NetNSInode: tmp.NetNSInode, VFInterfaceName: tmp.VFInterfaceName}
+
+	if _, isVF := link.(*netlink.Device); !isVF {
+		reaped.Action = ReapActionDeleted
+		if reaped.Err = DeleteInterface(name, netNS, logger); reaped.Err == nil {
+			delete(refCounts.tmpLinks, name)
+		}
+		return reaped
+	}
+
+	reaped.Action = ReapActionMovedBack
+	if !netNS.Equal(hostNetNS) {
+		if reaped.Err = MoveInterfaceToAnotherNamespace(name, netNS, hostNetNS, logger); reaped.Err != nil {
+			return reaped
+		}
+	}
+	// the VF stays with the temporary name in the forwarder netNS if its original name is not known
+	delete(refCounts.tmpLinks, name)
+	if tmp.VFInterfaceName == "" {
+		reaped.Err = errors.Errorf("original name of VF %s is not known", name)
+		return reaped
+	}
+	if reaped.Err = RenameInterface(name, tmp.VFInterfaceName, hostNetNS, logger); reaped.Err != nil {
+		refCounts.tmpLinks[name] = tmp
+	}
+	return reaped
+}
+
+func findLink(netNS netns.NsHandle, name string) (netlink.Link, error) {
+	handle, err := netlink.NewHandleAt(netNS)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create netlink handle for netNS %v", netNS)
+	}
+	defer handle.Close()
+	return handle.LinkByName(name)
+}
@@ -0,0 +1,341 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package inject
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// vfRef keeps the connections using the VF and where the VF is moved to
+type vfRef struct {
+	Connections     []string        `json:"connections"`
+	NetNSInode      uint64          `json:"netnsInode,omitempty"`
+	VFInterfaceName string          `json:"vfInterfaceName,omitempty"`
+	VFPCIAddress    string          `json:"vfPciAddress,omitempty"`
+	IfName          string          `json:"ifName,omitempty"`
+	Properties      *linkProperties `json:"properties,omitempty"`
+	RDMADevice      string          `json:"rdmaDevice,omitempty"`
+}
+
+// tmpLink is a link renamed to a temporary name by the forwarder, the orphan reaper restores or deletes it
+type tmpLink struct {
+	NetNSInode uint64 `json:"netnsInode,omitempty"`
+	// VFInterfaceName is the original name of the VF, the VF is renamed back to it instead of being deleted
+	VFInterfaceName string `json:"vfInterfaceName,omitempty"`
+}
+
+// stateFileVersion is the version of the state file format, the unversioned format keeps only the references
+const stateFileVersion = 2
+
+type state struct {
+	Version  int                 `json:"version"`
+	Refs     map[string]*vfRef   `json:"refs"`
+	TmpLinks map[string]*tmpLink `json:"tmpLinks,omitempty"`
+}
+
+// refCountStore keeps the VF references keyed by VF PCI address (or VF interface name), optionally
+// persisted to a state file. Connection IDs are kept instead of plain counters, so a connection
+// refreshed after a forwarder restart is not counted twice.
+type refCountStore struct {
+	sync.Mutex
+	refs      map[string]*vfRef
+	stateFile string
+	// tmpLinks are the links renamed to the temporary names keyed by the temporary name
+	tmpLinks map[string]*tmpLink
+	// lastSeen is the time of the last Request of the connection, it is not persisted
+	lastSeen map[string]time.Time
+	// netNSHandles keep the pod netNSes open, so the VFs can be reclaimed even if the pod is deleted before Close.
+	// The netNS watcher reclaims the VF and closes the handle once the pod netNS is deleted.
+	netNSHandles map[string]netns.NsHandle
+	// reaperStarted and watcherStarted are set once the background routines of the store are started
+	reaperStarted, watcherStarted bool
+}
+
+// sharedStores are the stores persisted to the state files, the inject client and server using the same state
+// file share the store instead of overwriting each other's references
+var sharedStores = struct {
+	sync.Mutex
+	stores map[string]*refCountStore
+}{
+	stores: make(map[string]*refCountStore),
+}
+
+func newRefCountStore(stateFile string) *refCountStore {
+	return &refCountStore{
+		refs:         make(map[string]*vfRef),
+		stateFile:    stateFile,
+		tmpLinks:     make(map[string]*tmpLink),
+		lastSeen:     make(map[string]time.Time),
+		netNSHandles: make(map[string]netns.NsHandle),
+	}
+}
+
+// load reads the references from the state file, a missing file is not an error
+func (s *refCountStore) load() error {
+	if s.stateFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.stateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to read inject state file %s", s.stateFile)
+	}
+	st, err := parseState(data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse inject state file %s", s.stateFile)
+	}
+	refs := st.Refs
+	if refs == nil {
+		refs = make(map[string]*vfRef)
+	}
+	s.refs = refs
+	if st.TmpLinks != nil {
+		s.tmpLinks = st.TmpLinks
+	}
+	// give the connections known before the restart a chance to be refreshed
+	now := time.Now()
+	for _, ref := range refs {
+		for _, connID := range ref.Connections {
+			s.lastSeen[connID] = now
+		}
+	}
+	return nil
+}
+
+// save writes the references to the state file, the file is replaced atomically
+func (s *refCountStore) save() error {
+	if s.stateFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(&state{
+		Version:  stateFileVersion,
+		Refs:     s.refs,
+		TmpLinks: s.tmpLinks,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal inject state")
+	}
+	if err = os.MkdirAll(filepath.Dir(s.stateFile), 0o700); err != nil {
+		return errors.Wrapf(err, "failed to create directory for inject state file %s", s.stateFile)
+	}
+	tmpFile := s.stateFile + ".tmp"
+	if err = os.WriteFile(tmpFile, data, 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write inject state file %s", tmpFile)
+	}
+	if err = os.Rename(tmpFile, s.stateFile); err != nil {
+		return errors.Wrapf(err, "failed to replace inject state file %s", s.stateFile)
+	}
+	return nil
+}
+
+// parseState parses both the versioned state and the unversioned one keeping only the references
+func parseState(data []byte) (*state, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	st := &state{}
+	if _, ok := fields["version"]; ok {
+		if err := json.Unmarshal(data, st); err != nil {
+			return nil, err
+		}
+		return st, nil
+	}
+	if err := json.Unmarshal(data, &st.Refs); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// inc adds the connection to the VF references and returns the number of connections using the VF.
+// newRef is stored if the VF is not referenced yet.
+func (s *refCountStore) inc(key, connID string, newRef *vfRef) int {
+	s.lastSeen[connID] = time.Now()
+	ref, ok := s.refs[key]
+	if !ok {
+		ref = newRef
+		s.refs[key] = ref
+	}
+	for _, id := range ref.Connections {
+		if id == connID {
+			return len(ref.Connections)
+		}
+	}
+	ref.Connections = append(ref.Connections, connID)
+	return len(ref.Connections)
+}
+
+// lookup returns the VF references if the connection is using the VF
+func (s *refCountStore) lookup(key, connID string) (*vfRef, bool) {
+	ref, ok := s.refs[key]
+	if !ok {
+		return nil, false
+	}
+	for _, id := range ref.Connections {
+		if id == connID {
+			return ref, true
+		}
+	}
+	return nil, false
+}
+
+// dec removes the connection from the VF references and returns the number of connections still using the VF,
+// the VF is forgotten when there are none. The ok result reports whether the connection was referencing the VF.
+func (s *refCountStore) dec(key, connID string) (count int, ok bool) {
+	delete(s.lastSeen, connID)
+	ref, ok := s.refs[key]
+	if !ok {
+		return 0, false
+	}
+	ok = false
+	for i, id := range ref.Connections {
+		if id == connID {
+			ref.Connections = append(ref.Connections[:i], ref.Connections[i+1:]...)
+			ok = true
+			break
+		}
+	}
+	if len(ref.Connections) == 0 {
+		s.forget(key)
+	}
+	return len(ref.Connections), ok
+}
+
+// forget drops the VF references and releases the pod netNS kept for the VF
+func (s *refCountStore) forget(key string) {
+	if ref, ok := s.refs[key]; ok {
+		for _, connID := range ref.Connections {
+			delete(s.lastSeen, connID)
+		}
+		delete(s.refs, key)
+	}
+	if handle, ok := s.netNSHandles[key]; ok {
+		_ = handle.Close()
+		delete(s.netNSHandles, key)
+	}
+}
+
+// keepNetNS keeps a duplicate of the pod netNS handle for the VF until it is forgotten
+func (s *refCountStore) keepNetNS(key string, handle netns.NsHandle) {
+	if _, ok := s.netNSHandles[key]; ok || !handle.IsOpen() {
+		return
+	}
+	fd, err := unix.Dup(int(handle))
+	if err != nil {
+		return
+	}
+	s.netNSHandles[key] = netns.NsHandle(fd)
+}
+
+// openNetNS returns the pod netNS of the VF, the kept handle is preferred since the pod may already be gone.
+// The returned close function must be called when the handle is not needed anymore.
+func (s *refCountStore) openNetNS(key string) (handle netns.NsHandle, closeFunc func(), err error) {
+	if handle, ok := s.netNSHandles[key]; ok {
+		return handle, func() {}, nil
+	}
+	ref, ok := s.refs[key]
+	if !ok {
+		return -1, nil, errors.Errorf("no reference for VF %s", key)
+	}
+	if handle, err = nshandle.FromInode(ref.NetNSInode); err != nil {
+		return -1, nil, err
+	}
+	return handle, func() { _ = handle.Close() }, nil
+}
+
+// vfInterfaceName returns the original name of the VF known as ifName in the netNS with the inode
+func (s *refCountStore) vfInterfaceName(inode uint64, ifName string) string {
+	for _, ref := range s.refs {
+		if ref.NetNSInode == inode && ref.IfName == ifName {
+			return ref.VFInterfaceName
+		}
+	}
+	return ""
+}
+
+// touch records the connection Request time
+func (s *refCountStore) touch(connID string) {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.lastSeen[connID]; ok {
+		s.lastSeen[connID] = time.Now()
+	}
+}
+
+func newRefCountStoreWithOptions(o *options) *refCountStore {
+	refCounts := getRefCountStore(o.stateFile)
+
+	refCounts.Lock()
+	defer refCounts.Unlock()
+
+	if o.reaperOptions != nil && !refCounts.reaperStarted {
+		refCounts.reaperStarted = true
+		reaper := &orphanReaper{
+			refCounts: refCounts,
+			options:   o.reaperOptions,
+		}
+		go reaper.run(o.reaperCtx)
+	}
+	if o.watcherCtx != nil && !refCounts.watcherStarted {
+		refCounts.watcherStarted = true
+		watcher := &netNSWatcher{
+			refCounts: refCounts,
+			interval:  o.watchInterval,
+		}
+		go watcher.run(o.watcherCtx)
+	}
+	return refCounts
+}
+
+// getRefCountStore returns the store of the state file, it is loaded once and shared by all the users of the
+// state file. The stores without the state file are not shared.
+func getRefCountStore(stateFile string) *refCountStore {
+	if stateFile != "" {
+		stateFile = filepath.Clean(stateFile)
+		sharedStores.Lock()
+		defer sharedStores.Unlock()
+		if refCounts, ok := sharedStores.stores[stateFile]; ok {
+			return refCounts
+		}
+	}
+
+	refCounts := newRefCountStore(stateFile)
+	if err := refCounts.load(); err != nil {
+		log.L().Errorf("inject: starting with empty VF reference counts: %v", err)
+	}
+	if stateFile != "" {
+		sharedStores.stores[stateFile] = refCounts
+	}
+	return refCounts
+}
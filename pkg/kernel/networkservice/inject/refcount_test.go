@@ -0,0 +1,158 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+//go:build linux
+// +build linux
+
+package inject
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefCountStore_StateFile(t *testing.T) {
+	samples := []struct {
+		Name     string
+		Data     string
+		Refs     map[string]*vfRef
+		TmpLinks map[string]*tmpLink
+		Error    bool
+	}{
+		{
+			Name: "versioned",
+			Data: `{"version":2,"refs":{"0000:01:00.1":{"connections":["conn-1","conn-2"],"netnsInode":4026532281,` +
+				`"vfInterfaceName":"ens1f0v1","vfPciAddress":"0000:01:00.1","ifName":"nsm-1"}},` +
+				`"tmpLinks":{"tmp-0a1b2c3d":{"netnsInode":4026532281,"vfInterfaceName":"ens1f0v2"}}}`,
+			Refs: map[string]*vfRef{
+				"0000:01:00.1": {
+					Connections:     []string{"conn-1", "conn-2"},
+					NetNSInode:      4026532281,
+					VFInterfaceName: "ens1f0v1",
+					VFPCIAddress:    "0000:01:00.1",
+					IfName:          "nsm-1",
+				},
+			},
+			TmpLinks: map[string]*tmpLink{
+				"tmp-0a1b2c3d": {NetNSInode: 4026532281, VFInterfaceName: "ens1f0v2"},
+			},
+		},
+		{
+			Name: "legacy",
+			Data: `{"0000:01:00.1":{"connections":["conn-1"],"netnsInode":4026532281,"vfInterfaceName":"ens1f0v1"}}`,
+			Refs: map[string]*vfRef{
+				"0000:01:00.1": {
+					Connections:     []string{"conn-1"},
+					NetNSInode:      4026532281,
+					VFInterfaceName: "ens1f0v1",
+				},
+			},
+			TmpLinks: map[string]*tmpLink{},
+		},
+		{
+			Name:     "empty",
+			Data:     `{"version":2,"refs":null}`,
+			Refs:     map[string]*vfRef{},
+			TmpLinks: map[string]*tmpLink{},
+		},
+		{
+			Name:  "corrupt",
+			Data:  `{"version":2,"refs":{"0000:01:00.1":`,
+			Error: true,
+		},
+		{
+			Name:  "wrong type",
+			Data:  `["0000:01:00.1"]`,
+			Error: true,
+		},
+	}
+	for _, sample := range samples {
+		t.Run(sample.Name, func(t *testing.T) {
+			stateFile := filepath.Join(t.TempDir(), "inject.json")
+			require.NoError(t, os.WriteFile(stateFile, []byte(sample.Data), 0o600))
+
+			s := newRefCountStore(stateFile)
+			err := s.load()
+			if sample.Error {
+				require.Error(t, err)
+				require.Empty(t, s.refs)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, sample.Refs, s.refs)
+			require.Equal(t, sample.TmpLinks, s.tmpLinks)
+			for _, ref := range sample.Refs {
+				for _, connID := range ref.Connections {
+					require.Contains(t, s.lastSeen, connID)
+				}
+			}
+		})
+	}
+}
+
+func TestRefCountStore_SaveLoad(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state", "inject.json")
+
+	s := newRefCountStore(stateFile)
+	require.NoError(t, s.load())
+	require.Empty(t, s.refs)
+
+	s.inc("0000:01:00.1", "conn-1", &vfRef{
+		NetNSInode:      4026532281,
+		VFInterfaceName: "ens1f0v1",
+		VFPCIAddress:    "0000:01:00.1",
+		IfName:          "nsm-1",
+		Properties:      &linkProperties{MTU: 9000},
+	})
+	s.inc("0000:01:00.1", "conn-2", nil)
+	s.tmpLinks["tmp-0a1b2c3d"] = &tmpLink{NetNSInode: 4026532281}
+	require.NoError(t, s.save())
+
+	// the state file is replaced by renaming the temporary file
+	_, err := os.Stat(stateFile + ".tmp")
+	require.True(t, os.IsNotExist(err))
+	info, err := os.Stat(stateFile)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	loaded := newRefCountStore(stateFile)
+	require.NoError(t, loaded.load())
+	require.Equal(t, s.refs, loaded.refs)
+	require.Equal(t, s.tmpLinks, loaded.tmpLinks)
+
+	count, ok := loaded.dec("0000:01:00.1", "conn-1")
+	require.True(t, ok)
+	require.Equal(t, 1, count)
+	require.NoError(t, loaded.save())
+
+	reloaded := newRefCountStore(stateFile)
+	require.NoError(t, reloaded.load())
+	require.Equal(t, []string{"conn-2"}, reloaded.refs["0000:01:00.1"].Connections)
+}
+
+func TestRefCountStore_NoStateFile(t *testing.T) {
+	s := newRefCountStore("")
+	require.NoError(t, s.load())
+	require.NoError(t, s.save())
+
+	s = newRefCountStore(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, s.load())
+	require.Empty(t, s.refs)
+}
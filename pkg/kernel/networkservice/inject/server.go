@@ -26,7 +26,6 @@ package inject
 
 import (
 	"context"
-	"sync"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
@@ -41,14 +40,18 @@ import (
 )
 
 type injectServer struct {
-	vfRefCountMap   map[string]int
-	vfRefCountMutex sync.Mutex
+	refCounts *refCountStore
+	options   *options
 }
 
 // NewServer - returns a new networkservice.NetworkServiceServer that moves given network interface into the Client's
 // pod network namespace on Request and back to Forwarder's network namespace on Close
-func NewServer() networkservice.NetworkServiceServer {
-	return &injectServer{vfRefCountMap: make(map[string]int)}
+func NewServer(opts ...Option) networkservice.NetworkServiceServer {
+	o := newOptions(opts...)
+	return &injectServer{
+		refCounts: newRefCountStoreWithOptions(o),
+		options:   o,
+	}
 }
 
 func (s *injectServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
@@ -56,6 +59,7 @@ func (s *injectServer) Request(ctx context.Context, request *networkservice.Netw
 	if mech == nil {
 		return next.Server(ctx).Request(ctx, request)
 	}
+	s.refCounts.touch(request.GetConnection().GetId())
 
 	var isEstablished bool
 	if vfConfig, ok := vfconfig.Load(ctx, metadata.IsClient(s)); ok {
@@ -63,7 +67,7 @@ func (s *injectServer) Request(ctx context.Context, request *networkservice.Netw
 	}
 
 	if !isEstablished {
-		if err := move(ctx, request.GetConnection(), s.vfRefCountMap, &s.vfRefCountMutex, metadata.IsClient(s), false); err != nil {
+		if err := move(ctx, request.GetConnection(), s.refCounts, s.options, metadata.IsClient(s), false); err != nil {
 			return nil, err
 		}
 	}
@@ -75,7 +79,7 @@ func (s *injectServer) Request(ctx context.Context, request *networkservice.Netw
 		moveCtx, cancelMove := postponeCtxFunc()
 		defer cancelMove()
 
-		if moveRenameErr := move(moveCtx, request.GetConnection(), s.vfRefCountMap, &s.vfRefCountMutex, metadata.IsClient(s), true); moveRenameErr != nil {
+		if moveRenameErr := move(moveCtx, request.GetConnection(), s.refCounts, s.options, metadata.IsClient(s), true); moveRenameErr != nil {
 			err = errors.Wrapf(err, "server request failed, failed to move back the interface: %s", moveRenameErr.Error())
 		}
 	}
@@ -84,7 +88,7 @@ func (s *injectServer) Request(ctx context.Context, request *networkservice.Netw
 }
 
 func (s *injectServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
-	moveRenameErr := move(ctx, conn, s.vfRefCountMap, &s.vfRefCountMutex, metadata.IsClient(s), true)
+	moveRenameErr := move(ctx, conn, s.refCounts, s.options, metadata.IsClient(s), true)
 	if moveRenameErr != nil {
 		return nil, moveRenameErr
 	}
@@ -0,0 +1,51 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package inject
+
+import (
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+)
+
+// moveTx records the completed steps of an interface move, so they can be undone in reverse order on failure
+type moveTx struct {
+	undos  []func() error
+	logger log.Logger
+}
+
+// do runs the step and records its undo if the step succeeds, undo may be nil if the step needs no undo
+func (t *moveTx) do(step, undo func() error) error {
+	if err := step(); err != nil {
+		return err
+	}
+	if undo != nil {
+		t.undos = append(t.undos, undo)
+	}
+	return nil
+}
+
+// rollback undoes the completed steps in reverse order, it goes on if an undo fails
+func (t *moveTx) rollback() {
+	for i := len(t.undos) - 1; i >= 0; i-- {
+		if err := t.undos[i](); err != nil {
+			t.logger.Warnf("Failed to roll back interface move step (%v)", err)
+		}
+	}
+	t.undos = nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package inject
+
+import (
+	"context"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+
+	kernellink "github.com/ljkiraly/sdk-kernel/pkg/kernel"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+const defaultWatchInterval = 5 * time.Second
+
+// WithNetNSWatcher - runs a background watcher until ctx is done. It notices the pods deleted before Close and moves
+// their VFs back to the forwarder netNS with the original names without waiting for Close. The pod netNSes are
+// checked every interval (5s by default if 0) and whenever a link appears in the forwarder netNS.
+func WithNetNSWatcher(ctx context.Context, interval time.Duration) Option {
+	return func(o *options) {
+		if interval == 0 {
+			interval = defaultWatchInterval
+		}
+		o.watcherCtx = ctx
+		o.watchInterval = interval
+	}
+}
+
+type netNSWatcher struct {
+	refCounts *refCountStore
+	interval  time.Duration
+}
+
+func (w *netNSWatcher) run(ctx context.Context) {
+	logger := log.FromContext(ctx).WithField("inject", "netNSWatcher")
+
+	// a VF moved to a pod netNS returns to the init netNS when the pod netNS is destroyed
+	linkCh := make(chan netlink.LinkUpdate)
+	if err := netlink.LinkSubscribeWithOptions(linkCh, ctx.Done(), netlink.LinkSubscribeOptions{
+		ErrorCallback: func(err error) {
+			logger.Warnf("Link subscription error (%v)", err)
+		},
+	}); err != nil {
+		logger.Warnf("Failed to subscribe to link updates, falling back to polling (%v)", err)
+		linkCh = nil
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-linkCh:
+			if !ok {
+				linkCh = nil
+				continue
+			}
+			if update.Header.Type != unix.RTM_NEWLINK {
+				continue
+			}
+		case <-ticker.C:
+		}
+		w.check(logger)
+	}
+}
+
+// check reclaims the VFs whose pod netNS has been deleted
+func (w *netNSWatcher) check(logger log.Logger) {
+	hostNetNS, err := nshandle.Current()
+	if err != nil {
+		logger.Errorf("Failed to get forwarder netNS: %v", err)
+		return
+	}
+	defer func() { _ = hostNetNS.Close() }()
+
+	w.refCounts.Lock()
+	defer w.refCounts.Unlock()
+
+	var changed bool
+	for key, ref := range w.refCounts.refs {
+		if ref.NetNSInode == 0 || !w.deleted(key, ref, hostNetNS) {
+			continue
+		}
+
+		name, err := reclaimVF(w.refCounts, key, hostNetNS, logger)
+		if err != nil {
+			// the VF is kept to be reclaimed by the next check
+			logger.Errorf("Failed to reclaim VF %s (%s) from deleted netNS %d: %v", key, name, ref.NetNSInode, err)
+			continue
+		}
+		logger.Infof("VF %s (%s) reclaimed from deleted netNS %d as %s", key, name, ref.NetNSInode, ref.VFInterfaceName)
+		w.refCounts.forget(key)
+		changed = true
+	}
+
+	if !changed {
+		return
+	}
+	if err := w.refCounts.save(); err != nil {
+		logger.Warnf("Failed to persist VF reference counts (%v)", err)
+	}
+}
+
+// deleted tells whether the pod netNS of the VF has been deleted. The pod netNS kept open by the forwarder is not
+// destroyed, it is deleted once no thread uses it and it is not bind mounted anymore. The kernel returns the VF of a
+// destroyed netNS to the forwarder netNS, so finding the VF there is the signal if the netNS is not kept. A netNS not
+// found by a scan may still be in use, so it is not considered deleted.
+func (w *netNSWatcher) deleted(key string, ref *vfRef, hostNetNS netns.NsHandle) bool {
+	if _, ok := w.refCounts.netNSHandles[key]; ok {
+		return !nshandle.Referenced(ref.NetNSInode)
+	}
+	if ref.VFPCIAddress == "" {
+		return false
+	}
+	link, _ := kernellink.FindHostDevice(ref.VFPCIAddress, "", hostNetNS)
+	return link != nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package macvlan
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/metadata"
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/inject"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// tmpIfNamePrefix is the prefix of the temporary names the links are created with in the forwarder netNS
+const tmpIfNamePrefix = "macv-"
+
+type createdKey struct{}
+
+func isCreated(ctx context.Context, isClient bool) bool {
+	_, ok := metadata.Map(ctx, isClient).Load(createdKey{})
+	return ok
+}
+
+func create(ctx context.Context, conn *networkservice.Connection, o *options, isClient bool) error {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil || isCreated(ctx, isClient) {
+		return nil
+	}
+	logger := log.FromContext(ctx).WithField("macvlan", "create")
+
+	parentLink, err := netlink.LinkByName(o.parentIfName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get parent interface: %v", o.parentIfName)
+	}
+
+	hostNetNS, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = hostNetNS.Close() }()
+
+	contNetNS, err := nshandle.FromURL(mech.GetNetNSURL())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = contNetNS.Close() }()
+
+	tmpIfName := getTempName(conn.GetId())
+	if err = netlink.LinkAdd(newLink(tmpIfName, parentLink, o)); err != nil {
+		return errors.Wrapf(err, "failed to create %s on %s", tmpIfName, o.parentIfName)
+	}
+	logger.Debugf("Interface %s created on %s in netNS %v", tmpIfName, o.parentIfName, hostNetNS)
+
+	ifName := mech.GetInterfaceName()
+	if err = inject.MoveInterfaceToAnotherNamespace(tmpIfName, hostNetNS, contNetNS, logger); err != nil {
+		cleanup(tmpIfName, hostNetNS, logger)
+		return err
+	}
+	if err = inject.RenameInterface(tmpIfName, ifName, contNetNS, logger); err != nil {
+		cleanup(tmpIfName, contNetNS, logger)
+		return err
+	}
+	if err = inject.UpInterface(ifName, contNetNS, logger); err != nil {
+		cleanup(ifName, contNetNS, logger)
+		return err
+	}
+	metadata.Map(ctx, isClient).Store(createdKey{}, struct{}{})
+	return nil
+}
+
+func del(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	if _, ok := metadata.Map(ctx, isClient).LoadAndDelete(createdKey{}); !ok {
+		return nil
+	}
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil
+	}
+	logger := log.FromContext(ctx).WithField("macvlan", "del")
+
+	contNetNS, err := nshandle.FromURL(mech.GetNetNSURL())
+	if err != nil {
+		// the child link is removed together with the target netNS
+		logger.Warnf("Can not open target netNS, might be deleted already (%v)", err)
+		return nil
+	}
+	defer func() { _ = contNetNS.Close() }()
+
+	return inject.DeleteInterface(mech.GetInterfaceName(), contNetNS, logger)
+}
+
+func newLink(name string, parentLink netlink.Link, o *options) netlink.Link {
+	attrs := netlink.NewLinkAttrs()
+	attrs.Name = name
+	attrs.ParentIndex = parentLink.Attrs().Index
+	if o.ipvlanMode != nil {
+		return &netlink.IPVlan{
+			LinkAttrs: attrs,
+			Mode:      *o.ipvlanMode,
+		}
+	}
+	return &netlink.Macvlan{
+		LinkAttrs: attrs,
+		Mode:      o.macvlanMode,
+	}
+}
+
+func cleanup(ifName string, netNS netns.NsHandle, logger log.Logger) {
+	if err := inject.DeleteInterface(ifName, netNS, logger); err != nil {
+		logger.Warnf("Failed to delete interface %s from netNS %v (%v)", ifName, netNS, err)
+	}
+}
+
+// getTempName returns an interface name unique per connection, so concurrent requests
+// don't collide in the forwarder netNS. The prefix differs from the inject one, so the inject reaper doesn't take
+// the macvlan links for its own.
+func getTempName(connID string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(connID))
+	return fmt.Sprintf("%s%08x", tmpIfNamePrefix, h.Sum32())
+}
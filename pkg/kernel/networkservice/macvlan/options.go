@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package macvlan
+
+import (
+	"github.com/vishvananda/netlink"
+)
+
+type options struct {
+	parentIfName string
+	macvlanMode  netlink.MacvlanMode
+	ipvlanMode   *netlink.IPVlanMode
+}
+
+// Option is an option pattern for NewClient, NewServer
+type Option func(o *options)
+
+// WithParentInterface - sets the forwarder interface the child links are created on, it is required
+func WithParentInterface(ifName string) Option {
+	return func(o *options) {
+		o.parentIfName = ifName
+	}
+}
+
+// WithMACVLANMode - sets the macvlan mode, netlink.MACVLAN_MODE_BRIDGE is used by default
+func WithMACVLANMode(mode netlink.MacvlanMode) Option {
+	return func(o *options) {
+		o.macvlanMode = mode
+	}
+}
+
+// WithIPVLANMode - creates ipvlan links in the given mode instead of macvlan ones
+func WithIPVLANMode(mode netlink.IPVlanMode) Option {
+	return func(o *options) {
+		o.ipvlanMode = &mode
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		macvlanMode: netlink.MACVLAN_MODE_BRIDGE,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.parentIfName == "" {
+		panic("macvlan: the parent interface is not set, use WithParentInterface")
+	}
+	return o
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package macvlan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+)
+
+func TestOptions(t *testing.T) {
+	o := newOptions(WithParentInterface("eth0"))
+	require.Equal(t, "eth0", o.parentIfName)
+	require.Equal(t, netlink.MACVLAN_MODE_BRIDGE, o.macvlanMode)
+	require.Nil(t, o.ipvlanMode)
+	require.IsType(t, &netlink.Macvlan{}, newLink("nsm", &netlink.Device{}, o))
+
+	o = newOptions(WithParentInterface("eth0"), WithMACVLANMode(netlink.MACVLAN_MODE_PRIVATE))
+	require.Equal(t, netlink.MACVLAN_MODE_PRIVATE, o.macvlanMode)
+
+	o = newOptions(WithParentInterface("eth0"), WithIPVLANMode(netlink.IPVLAN_MODE_L3))
+	require.Equal(t, netlink.IPVLAN_MODE_L3, *o.ipvlanMode)
+	require.IsType(t, &netlink.IPVlan{}, newLink("nsm", &netlink.Device{}, o))
+}
+
+func TestOptions_NoParentInterface(t *testing.T) {
+	require.Panics(t, func() { newOptions() })
+	require.Panics(t, func() { newOptions(WithParentInterface("")) })
+	require.Panics(t, func() { NewServer() })
+	require.Panics(t, func() { NewClient() })
+}
+
+func TestGetTempName(t *testing.T) {
+	name := getTempName("nsm-conn")
+	require.Equal(t, name, getTempName("nsm-conn"))
+	require.NotEqual(t, name, getTempName("nsm-other-conn"))
+	require.LessOrEqual(t, len(name), kernel.LinuxIfMaxLength)
+
+	// the inject reaper deletes the "tmp-*" links it doesn't know
+	require.True(t, strings.HasPrefix(name, tmpIfNamePrefix))
+	require.False(t, strings.HasPrefix(name, "tmp-"))
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build perm
+// +build perm
+
+package macvlan_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/core/chain"
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/macvlan"
+)
+
+const (
+	parentIfName = "nsm-mv-parent"
+	ifName       = "nsm-mv"
+)
+
+func TestMACVLANServer_CreateDeletePerm(t *testing.T) {
+	parent := addParent(t)
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		macvlan.NewServer(macvlan.WithParentInterface(parentIfName)),
+	)
+
+	request := newRequest(target)
+	conn, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+
+	l, err := handle.LinkByName(ifName)
+	require.NoError(t, err)
+	require.IsType(t, &netlink.Macvlan{}, l)
+	require.Equal(t, netlink.MACVLAN_MODE_BRIDGE, l.(*netlink.Macvlan).Mode)
+	require.Equal(t, parent.Attrs().Index, l.Attrs().ParentIndex)
+	require.NotZero(t, l.Attrs().Flags&net.FlagUp)
+
+	// no temporary link is left in the forwarder netNS
+	requireNoChildren(t, parent)
+
+	// a refresh keeps the link
+	request.Connection = conn
+	conn, err = server.Request(context.Background(), request)
+	require.NoError(t, err)
+	_, err = handle.LinkByName(ifName)
+	require.NoError(t, err)
+
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+	_, err = handle.LinkByName(ifName)
+	require.Error(t, err)
+}
+
+func TestMACVLANServer_RequestFailedPerm(t *testing.T) {
+	parent := addParent(t)
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		macvlan.NewServer(macvlan.WithParentInterface(parentIfName)),
+		injecterror.NewServer(),
+	)
+
+	_, err = server.Request(context.Background(), newRequest(target))
+	require.Error(t, err)
+
+	// the link is deleted if the Request fails
+	_, err = handle.LinkByName(ifName)
+	require.Error(t, err)
+	requireNoChildren(t, parent)
+}
+
+func TestMACVLANServer_NameTakenPerm(t *testing.T) {
+	parent := addParent(t)
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	require.NoError(t, handle.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  ifName + "-peer",
+	}))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		macvlan.NewServer(macvlan.WithParentInterface(parentIfName)),
+	)
+
+	_, err = server.Request(context.Background(), newRequest(target))
+	require.Error(t, err)
+
+	// the temporary link is deleted and the existing interface is left intact
+	requireNoChildren(t, parent)
+	l, err := handle.LinkByName(ifName)
+	require.NoError(t, err)
+	require.IsType(t, &netlink.Veth{}, l)
+}
+
+func newRequest(target netns.NsHandle) *networkservice.NetworkServiceRequest {
+	mechanism := kernel.New(fmt.Sprintf("fd://%d", int(target)))
+	kernel.ToMechanism(mechanism).SetInterfaceName(ifName)
+
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:        "nsm-conn",
+			Mechanism: mechanism,
+		},
+	}
+}
+
+// requireNoChildren checks there is no link created on the parent in the forwarder netNS
+func requireNoChildren(t *testing.T, parent netlink.Link) {
+	links, err := netlink.LinkList()
+	require.NoError(t, err)
+	for _, l := range links {
+		if _, ok := l.(*netlink.Macvlan); ok {
+			require.NotEqual(t, parent.Attrs().Index, l.Attrs().ParentIndex, "link %s is left", l.Attrs().Name)
+		}
+	}
+}
+
+// addParent adds the parent interface to the forwarder netNS, it is deleted on the test cleanup
+func addParent(t *testing.T) netlink.Link {
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: parentIfName},
+		PeerName:  parentIfName + "-p",
+	}))
+	parent, err := netlink.LinkByName(parentIfName)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = netlink.LinkDel(parent) })
+	return parent
+}
+
+func newNSHandle(t *testing.T) netns.NsHandle {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	baseHandle, err := netns.Get()
+	require.NoError(t, err)
+	defer func() {
+		_ = netns.Set(baseHandle)
+		_ = baseHandle.Close()
+	}()
+
+	newHandle, err := netns.New()
+	require.NoError(t, err)
+
+	return newHandle
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package netlinkcache
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/ljkiraly/sdk/pkg/networkservice/core/next"
+	"google.golang.org/grpc"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel"
+)
+
+type netlinkCacheClient struct {
+	cache *kernel.NetlinkHandleCache
+}
+
+// NewClient - returns a new networkservice.NetworkServiceClient that makes the following chain elements take
+// the netlink handles from the cache instead of creating them for every Request
+func NewClient(cache *kernel.NetlinkHandleCache) networkservice.NetworkServiceClient {
+	return &netlinkCacheClient{cache: cache}
+}
+
+func (c *netlinkCacheClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	ctx = kernel.WithNetlinkHandleCache(ctx, c.cache)
+	return next.Client(ctx).Request(ctx, request, opts...)
+}
+
+func (c *netlinkCacheClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	ctx = kernel.WithNetlinkHandleCache(ctx, c.cache)
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package netlinkcache provides chain element sharing the netlink handles of the net NSes between the
+// following chain elements
+package netlinkcache
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/ljkiraly/sdk/pkg/networkservice/core/next"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel"
+)
+
+type netlinkCacheServer struct {
+	cache *kernel.NetlinkHandleCache
+}
+
+// NewServer - returns a new networkservice.NetworkServiceServer that makes the following chain elements take
+// the netlink handles from the cache instead of creating them for every Request
+func NewServer(cache *kernel.NetlinkHandleCache) networkservice.NetworkServiceServer {
+	return &netlinkCacheServer{cache: cache}
+}
+
+func (s *netlinkCacheServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	ctx = kernel.WithNetlinkHandleCache(ctx, s.cache)
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *netlinkCacheServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	ctx = kernel.WithNetlinkHandleCache(ctx, s.cache)
+	return next.Server(ctx).Close(ctx, conn)
+}
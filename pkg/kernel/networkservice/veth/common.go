@@ -0,0 +1,148 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package veth
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/metadata"
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+
+	link "github.com/ljkiraly/sdk-kernel/pkg/kernel"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/peer"
+)
+
+const hostIfNamePrefix = "nsm-"
+
+type hostIfNameKey struct{}
+
+func loadHostIfName(ctx context.Context, isClient bool) (string, bool) {
+	rawValue, ok := metadata.Map(ctx, isClient).Load(hostIfNameKey{})
+	if !ok {
+		return "", false
+	}
+	hostIfName, ok := rawValue.(string)
+	return hostIfName, ok
+}
+
+func create(ctx context.Context, conn *networkservice.Connection, isClient bool) (err error) {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil
+	}
+	if _, ok := loadHostIfName(ctx, isClient); ok {
+		return nil
+	}
+	logger := log.FromContext(ctx).WithField("veth", "create")
+
+	contNetNS, err := nshandle.FromURL(mech.GetNetNSURL())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = contNetNS.Close() }()
+
+	// The host end stays in the forwarder netNS, the other end is created right in the target netNS
+	// with the requested name
+	ifName := mech.GetInterfaceName()
+	hostIfName := getHostIfName(conn.GetId())
+	attrs := netlink.NewLinkAttrs()
+	attrs.Name = hostIfName
+	if mtu := int(conn.GetContext().GetMTU()); mtu != 0 {
+		attrs.MTU = mtu
+	}
+	if err = netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs:     attrs,
+		PeerName:      ifName,
+		PeerNamespace: netlink.NsFd(contNetNS),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create veth pair %s - %s", hostIfName, ifName)
+	}
+	metadata.Map(ctx, isClient).Store(hostIfNameKey{}, hostIfName)
+	defer func() {
+		if err == nil {
+			return
+		}
+		if delErr := del(ctx, isClient); delErr != nil {
+			logger.Warnf("Failed to delete veth pair %s (%v)", hostIfName, delErr)
+		}
+	}()
+	logger.Debugf("Veth pair %s - %s created, %s is in netNS %v", hostIfName, ifName, ifName, contNetNS)
+
+	hostLink, err := netlink.LinkByName(hostIfName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find link %s", hostIfName)
+	}
+	if err = netlink.LinkSetUp(hostLink); err != nil {
+		return errors.Wrapf(err, "failed to setup link for the interface %v", hostLink)
+	}
+	peer.Store(ctx, isClient, hostLink)
+
+	netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mech.GetNetNSURL())
+	if err != nil {
+		return err
+	}
+	defer releaseHandle()
+
+	l, err := netlinkHandle.LinkByName(ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find link %s", ifName)
+	}
+	if err = netlinkHandle.LinkSetUp(l); err != nil {
+		return errors.Wrapf(err, "failed to setup link for the interface %v", l)
+	}
+	return nil
+}
+
+func del(ctx context.Context, isClient bool) error {
+	rawValue, ok := metadata.Map(ctx, isClient).LoadAndDelete(hostIfNameKey{})
+	if !ok {
+		return nil
+	}
+	peer.Delete(ctx, isClient)
+	logger := log.FromContext(ctx).WithField("veth", "del")
+
+	hostIfName, _ := rawValue.(string)
+	hostLink, err := netlink.LinkByName(hostIfName)
+	if err != nil {
+		// the pair is removed together with the target netNS
+		logger.Warnf("Can not find interface %s, might be deleted already (%v)", hostIfName, err)
+		return nil
+	}
+	if err = netlink.LinkDel(hostLink); err != nil {
+		return errors.Wrapf(err, "failed to delete veth pair %s", hostIfName)
+	}
+	logger.Debugf("Veth pair %s deleted", hostIfName)
+	return nil
+}
+
+// getHostIfName returns the host end name unique per connection
+func getHostIfName(connID string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(connID))
+	return fmt.Sprintf("%s%08x", hostIfNamePrefix, h.Sum32())
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package veth contains chain element that creates a veth pair with one end in a Client's pod network namespace
+package veth
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/core/next"
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/metadata"
+	"github.com/ljkiraly/sdk/pkg/tools/postpone"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+type vethServer struct{}
+
+// NewServer - returns a new networkservice.NetworkServiceServer that creates a veth pair on Request, with one end
+// in the Client's pod network namespace named per kernel mechanism and the other end in the Forwarder's network
+// namespace stored via peer.Store, and deletes the pair on Close.
+// It should be placed before connectioncontextkernel.NewServer() in the chain.
+func NewServer() networkservice.NetworkServiceServer {
+	return &vethServer{}
+}
+
+func (s *vethServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	_, isEstablished := loadHostIfName(ctx, metadata.IsClient(s))
+
+	if err := create(ctx, request.GetConnection(), metadata.IsClient(s)); err != nil {
+		return nil, err
+	}
+
+	postponeCtxFunc := postpone.ContextWithValues(ctx)
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil && !isEstablished {
+		delCtx, cancelDel := postponeCtxFunc()
+		defer cancelDel()
+
+		if delErr := del(delCtx, metadata.IsClient(s)); delErr != nil {
+			err = errors.Wrapf(err, "server request failed, failed to delete the veth pair: %s", delErr.Error())
+		}
+	}
+
+	return conn, err
+}
+
+func (s *vethServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	rv, err := next.Server(ctx).Close(ctx, conn)
+	if delErr := del(ctx, metadata.IsClient(s)); delErr != nil {
+		if err != nil {
+			return nil, errors.Wrapf(delErr, "close failed with error: %s", err.Error())
+		}
+		return nil, delErr
+	}
+	return rv, err
+}
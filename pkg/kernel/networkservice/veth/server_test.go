@@ -0,0 +1,181 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build perm
+// +build perm
+
+package veth_test
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"runtime"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/core/chain"
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/veth"
+)
+
+const (
+	connID = "nsm-conn"
+	ifName = "nsm-veth"
+	mtu    = 1400
+)
+
+func TestVethServer_CreateDeletePerm(t *testing.T) {
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		veth.NewServer(),
+	)
+
+	request := newRequest(target)
+	conn, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+
+	l, err := handle.LinkByName(ifName)
+	require.NoError(t, err)
+	require.IsType(t, &netlink.Veth{}, l)
+	require.Equal(t, mtu, l.Attrs().MTU)
+	require.NotZero(t, l.Attrs().Flags&net.FlagUp)
+
+	// the host end stays in the forwarder netNS
+	hostLink, err := netlink.LinkByName(hostIfName())
+	require.NoError(t, err)
+	require.Equal(t, l.Attrs().ParentIndex, hostLink.Attrs().Index)
+	require.Equal(t, mtu, hostLink.Attrs().MTU)
+	require.NotZero(t, hostLink.Attrs().Flags&net.FlagUp)
+
+	// a refresh keeps the pair
+	request.Connection = conn
+	conn, err = server.Request(context.Background(), request)
+	require.NoError(t, err)
+	_, err = netlink.LinkByName(hostIfName())
+	require.NoError(t, err)
+
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+	_, err = netlink.LinkByName(hostIfName())
+	require.Error(t, err)
+	_, err = handle.LinkByName(ifName)
+	require.Error(t, err)
+}
+
+func TestVethServer_RequestFailedPerm(t *testing.T) {
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		veth.NewServer(),
+		injecterror.NewServer(),
+	)
+
+	_, err = server.Request(context.Background(), newRequest(target))
+	require.Error(t, err)
+
+	// the pair is deleted if the Request fails
+	_, err = netlink.LinkByName(hostIfName())
+	require.Error(t, err)
+	_, err = handle.LinkByName(ifName)
+	require.Error(t, err)
+}
+
+func TestVethServer_NameTakenPerm(t *testing.T) {
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	require.NoError(t, handle.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  ifName + "-peer",
+	}))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		veth.NewServer(),
+	)
+
+	_, err = server.Request(context.Background(), newRequest(target))
+	require.Error(t, err)
+
+	// no host end is left and the existing interface is left intact
+	_, err = netlink.LinkByName(hostIfName())
+	require.Error(t, err)
+	_, err = handle.LinkByName(ifName)
+	require.NoError(t, err)
+}
+
+func newRequest(target netns.NsHandle) *networkservice.NetworkServiceRequest {
+	mechanism := kernel.New(fmt.Sprintf("fd://%d", int(target)))
+	kernel.ToMechanism(mechanism).SetInterfaceName(ifName)
+
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:        connID,
+			Mechanism: mechanism,
+			Context:   &networkservice.ConnectionContext{MTU: mtu},
+		},
+	}
+}
+
+// hostIfName returns the name of the host end of the pair, the same as the veth chain element does
+func hostIfName() string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(connID))
+	return fmt.Sprintf("nsm-%08x", h.Sum32())
+}
+
+func newNSHandle(t *testing.T) netns.NsHandle {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	baseHandle, err := netns.Get()
+	require.NoError(t, err)
+	defer func() {
+		_ = netns.Set(baseHandle)
+		_ = baseHandle.Close()
+	}()
+
+	newHandle, err := netns.New()
+	require.NoError(t, err)
+
+	return newHandle
+}
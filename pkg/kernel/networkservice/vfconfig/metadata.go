@@ -29,6 +29,8 @@ import (
 
 type key struct{}
 
+type membersKey struct{}
+
 // VFConfig is a config for VF
 type VFConfig struct {
 	// PFInterfaceName is a parent PF net interface name
@@ -87,3 +89,25 @@ func LoadAndDelete(ctx context.Context, isClient bool) (config *VFConfig, ok boo
 	config, ok = rawValue.(*VFConfig)
 	return config, ok
 }
+
+// StoreMembers sets the VFConfigs of the VFs aggregated for the connection in per Connection.Id metadata
+func StoreMembers(ctx context.Context, isClient bool, configs []*VFConfig) {
+	metadata.Map(ctx, isClient).Store(membersKey{}, configs)
+}
+
+// DeleteMembers deletes the VFConfigs of the aggregated VFs stored in per Connection.Id metadata
+func DeleteMembers(ctx context.Context, isClient bool) {
+	metadata.Map(ctx, isClient).Delete(membersKey{})
+}
+
+// LoadMembers returns the VFConfigs of the aggregated VFs stored in per Connection.Id metadata, or nil if no
+// value is present.
+// The ok result indicates whether value was found in the per Connection.Id metadata.
+func LoadMembers(ctx context.Context, isClient bool) (configs []*VFConfig, ok bool) {
+	rawValue, ok := metadata.Map(ctx, isClient).Load(membersKey{})
+	if !ok {
+		return
+	}
+	configs, ok = rawValue.([]*VFConfig)
+	return configs, ok
+}
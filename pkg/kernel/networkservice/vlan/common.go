@@ -0,0 +1,149 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package vlan
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+
+	link "github.com/ljkiraly/sdk-kernel/pkg/kernel"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/vlanlink"
+)
+
+// ParentIfNameKey - kernel mechanism parameter key of the forwarder interface the VLAN sub-interface is created on
+const ParentIfNameKey = "vlanParentIfName"
+
+func create(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	mechanism := kernel.ToMechanism(conn.GetMechanism())
+	if mechanism == nil || mechanism.GetVLAN() == 0 {
+		return nil
+	}
+	parentIfName := mechanism.GetParameters()[ParentIfNameKey]
+	if parentIfName == "" {
+		return nil
+	}
+	logger := log.FromContext(ctx).WithField("vlan", "create")
+
+	netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
+	if err != nil {
+		return err
+	}
+	defer releaseHandle()
+
+	ifName := mechanism.GetInterfaceName()
+	vlanID := int(mechanism.GetVLAN())
+	if l, linkErr := netlinkHandle.LinkByName(ifName); linkErr == nil {
+		if vlanLink, ok := l.(*netlink.Vlan); ok && vlanLink.VlanId == vlanID {
+			logger.Debugf("VLAN sub-interface %s (vlan %d) already exists", ifName, vlanID)
+			vlanlink.Store(ctx, isClient, l)
+			return nil
+		}
+		return errors.Errorf("interface %s already exists in the target netNS and it is not a VLAN %d sub-interface", ifName, vlanID)
+	}
+
+	parentLink, err := netlink.LinkByName(parentIfName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get VLAN parent interface: %v", parentIfName)
+	}
+
+	targetNetNS, err := nshandle.FromURL(mechanism.GetNetNSURL())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = targetNetNS.Close() }()
+
+	// The sub-interface is created right in the target netNS with the requested name, so it never
+	// appears in the forwarder netNS and its name can't collide with the forwarder interfaces.
+	attrs := netlink.NewLinkAttrs()
+	attrs.Name = ifName
+	attrs.ParentIndex = parentLink.Attrs().Index
+	attrs.Namespace = netlink.NsFd(targetNetNS)
+	if err = netlink.LinkAdd(&netlink.Vlan{
+		LinkAttrs:    attrs,
+		VlanId:       vlanID,
+		VlanProtocol: netlink.VLAN_PROTOCOL_8021Q,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create VLAN sub-interface %s on %s (vlan %d)", ifName, parentIfName, vlanID)
+	}
+
+	if err = setUp(ctx, netlinkHandle, ifName, isClient); err != nil {
+		// the sub-interface is not left behind half configured
+		vlanlink.LoadAndDelete(ctx, isClient)
+		if l, linkErr := netlinkHandle.LinkByName(ifName); linkErr == nil {
+			if delErr := netlinkHandle.LinkDel(l); delErr != nil {
+				err = errors.Wrapf(err, "failed to delete VLAN sub-interface %s: %s", ifName, delErr.Error())
+			}
+		}
+		return err
+	}
+	logger.Debugf("VLAN sub-interface %s created on %s (vlan %d) in netNS %v", ifName, parentIfName, vlanID, targetNetNS)
+	return nil
+}
+
+func setUp(ctx context.Context, netlinkHandle *netlink.Handle, ifName string, isClient bool) error {
+	l, err := netlinkHandle.LinkByName(ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find link %s", ifName)
+	}
+	vlanlink.Store(ctx, isClient, l)
+
+	if err = netlinkHandle.LinkSetUp(l); err != nil {
+		return errors.Wrapf(err, "failed to setup link for the interface %v", l)
+	}
+	return nil
+}
+
+func del(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	if _, ok := vlanlink.LoadAndDelete(ctx, isClient); !ok {
+		return nil
+	}
+	mechanism := kernel.ToMechanism(conn.GetMechanism())
+	if mechanism == nil {
+		return nil
+	}
+	logger := log.FromContext(ctx).WithField("vlan", "del")
+
+	netlinkHandle, releaseHandle, err := link.AcquireNetlinkHandle(ctx, mechanism.GetNetNSURL())
+	if err != nil {
+		// the sub-interface is removed together with the target netNS
+		logger.Warnf("Can not open target netNS, might be deleted already (%v)", err)
+		return nil
+	}
+	defer releaseHandle()
+
+	ifName := mechanism.GetInterfaceName()
+	l, err := netlinkHandle.LinkByName(ifName)
+	if err != nil {
+		logger.Warnf("Can not find interface %s, might be deleted already (%v)", ifName, err)
+		return nil
+	}
+	if err = netlinkHandle.LinkDel(l); err != nil {
+		return errors.Wrapf(err, "failed to delete VLAN sub-interface %s", ifName)
+	}
+	logger.Debugf("VLAN sub-interface %s deleted", ifName)
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package vlan contains chain element that creates 802.1Q sub-interface for the kernel mechanism
+// in a Client's pod network namespace
+package vlan
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/core/next"
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/metadata"
+	"github.com/ljkiraly/sdk/pkg/tools/postpone"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/vlanlink"
+)
+
+type vlanServer struct{}
+
+// NewServer - returns a new networkservice.NetworkServiceServer that creates a VLAN sub-interface of the
+// ParentIfNameKey interface in the Client's pod network namespace on Request and deletes it on Close.
+// It should be placed before connectioncontextkernel.NewServer() in the chain.
+func NewServer() networkservice.NetworkServiceServer {
+	return &vlanServer{}
+}
+
+func (s *vlanServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	_, isEstablished := vlanlink.Load(ctx, metadata.IsClient(s))
+
+	if err := create(ctx, request.GetConnection(), metadata.IsClient(s)); err != nil {
+		return nil, err
+	}
+
+	postponeCtxFunc := postpone.ContextWithValues(ctx)
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil && !isEstablished {
+		delCtx, cancelDel := postponeCtxFunc()
+		defer cancelDel()
+
+		if delErr := del(delCtx, request.GetConnection(), metadata.IsClient(s)); delErr != nil {
+			err = errors.Wrapf(err, "server request failed, failed to delete the VLAN sub-interface: %s", delErr.Error())
+		}
+	}
+
+	return conn, err
+}
+
+func (s *vlanServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	rv, err := next.Server(ctx).Close(ctx, conn)
+	if delErr := del(ctx, conn, metadata.IsClient(s)); delErr != nil {
+		if err != nil {
+			return nil, errors.Wrapf(delErr, "close failed with error: %s", err.Error())
+		}
+		return nil, delErr
+	}
+	return rv, err
+}
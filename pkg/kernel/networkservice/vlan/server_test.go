@@ -0,0 +1,185 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build perm
+// +build perm
+
+package vlan_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk/pkg/networkservice/core/chain"
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/ljkiraly/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/networkservice/vlan"
+)
+
+const (
+	parentIfName = "nsm-vlan-par"
+	ifName       = "nsm-vlan"
+	vlanID       = 100
+)
+
+func TestVLANServer_CreateDeletePerm(t *testing.T) {
+	addParent(t)
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		vlan.NewServer(),
+	)
+
+	request := newRequest(target)
+	conn, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+
+	l, err := handle.LinkByName(ifName)
+	require.NoError(t, err)
+	require.IsType(t, &netlink.Vlan{}, l)
+	require.Equal(t, vlanID, l.(*netlink.Vlan).VlanId)
+	require.NotZero(t, l.Attrs().Flags&net.FlagUp)
+
+	// the sub-interface is not created in the forwarder netNS
+	_, err = netlink.LinkByName(ifName)
+	require.Error(t, err)
+
+	// a refresh keeps the sub-interface
+	request.Connection = conn
+	conn, err = server.Request(context.Background(), request)
+	require.NoError(t, err)
+	_, err = handle.LinkByName(ifName)
+	require.NoError(t, err)
+
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+	_, err = handle.LinkByName(ifName)
+	require.Error(t, err)
+}
+
+func TestVLANServer_RequestFailedPerm(t *testing.T) {
+	addParent(t)
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		vlan.NewServer(),
+		injecterror.NewServer(),
+	)
+
+	_, err = server.Request(context.Background(), newRequest(target))
+	require.Error(t, err)
+
+	// the sub-interface is deleted if the Request fails
+	_, err = handle.LinkByName(ifName)
+	require.Error(t, err)
+}
+
+func TestVLANServer_NameTakenPerm(t *testing.T) {
+	addParent(t)
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	handle, err := netlink.NewHandleAt(target)
+	require.NoError(t, err)
+	defer handle.Close()
+
+	require.NoError(t, handle.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  ifName + "-peer",
+	}))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		vlan.NewServer(),
+	)
+
+	_, err = server.Request(context.Background(), newRequest(target))
+	require.Error(t, err)
+
+	// the interface which is not a VLAN sub-interface is left intact
+	l, err := handle.LinkByName(ifName)
+	require.NoError(t, err)
+	require.IsType(t, &netlink.Veth{}, l)
+}
+
+func newRequest(target netns.NsHandle) *networkservice.NetworkServiceRequest {
+	mechanism := kernel.New(fmt.Sprintf("fd://%d", int(target)))
+	kernel.ToMechanism(mechanism).SetInterfaceName(ifName)
+	kernel.ToMechanism(mechanism).SetVLAN(vlanID)
+	mechanism.GetParameters()[vlan.ParentIfNameKey] = parentIfName
+
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:        "nsm-conn",
+			Mechanism: mechanism,
+		},
+	}
+}
+
+// addParent adds the parent interface to the forwarder netNS, it is deleted on the test cleanup
+func addParent(t *testing.T) {
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: parentIfName},
+		PeerName:  parentIfName + "-p",
+	}))
+	t.Cleanup(func() {
+		if l, err := netlink.LinkByName(parentIfName); err == nil {
+			_ = netlink.LinkDel(l)
+		}
+	})
+}
+
+func newNSHandle(t *testing.T) netns.NsHandle {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	baseHandle, err := netns.Get()
+	require.NoError(t, err)
+	defer func() {
+		_ = netns.Set(baseHandle)
+		_ = baseHandle.Close()
+	}()
+
+	newHandle, err := netns.New()
+	require.NoError(t, err)
+
+	return newHandle
+}
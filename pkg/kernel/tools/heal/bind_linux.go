@@ -0,0 +1,165 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package heal
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	protocolICMP     = 1
+	protocolIPv6ICMP = 58
+)
+
+// bindToDevice returns the net.Dialer control function binding the socket to the interface (SO_BINDTODEVICE)
+func bindToDevice(ifName string) func(network, address string, c syscall.RawConn) error {
+	if ifName == "" {
+		return nil
+	}
+	return func(_, _ string, c syscall.RawConn) error {
+		var bindErr error
+		if err := c.Control(func(fd uintptr) {
+			bindErr = unix.BindToDevice(int(fd), ifName)
+		}); err != nil {
+			return err
+		}
+		return errors.Wrapf(bindErr, "failed to bind the socket to the interface %s", ifName)
+	}
+}
+
+// newICMPPingerFactory returns the ICMP echo pinger factory, the pingers are bound to the interface if it is known
+func newICMPPingerFactory(ifName string) PingerFactory {
+	if ifName == "" {
+		return &defaultPingerFactory{}
+	}
+	return &icmpPingerFactory{ifName: ifName}
+}
+
+// icmpPingerFactory creates pingers sending ICMP echo requests with the unprivileged ICMP sockets bound to the
+// interface, so the net.ipv4.ping_group_range sysctl should allow them in the net NS of the connection
+type icmpPingerFactory struct {
+	ifName string
+}
+
+func (f *icmpPingerFactory) CreatePinger(srcIP, dstIP string, timeout time.Duration, count int) Pinger {
+	return f.createPinger(srcIP, dstIP, timeout, defaultInterval(timeout, count), count)
+}
+
+func (f *icmpPingerFactory) createPinger(srcIP, dstIP string, timeout, interval time.Duration, count int) Pinger {
+	src, dst := net.ParseIP(srcIP), net.ParseIP(dstIP)
+	seq := 0
+	return newProbePinger(timeout, interval, count, func(timeout time.Duration) (bool, error) {
+		c, err := listenICMP(src, f.ifName)
+		if err != nil {
+			return false, err
+		}
+		defer func() { _ = c.Close() }()
+
+		seq++
+		return echo(c, src.To4() == nil, dst, seq, timeout)
+	})
+}
+
+func listenICMP(src net.IP, ifName string) (net.PacketConn, error) {
+	family, proto := unix.AF_INET, unix.IPPROTO_ICMP
+	var sa unix.Sockaddr
+	if ip4 := src.To4(); ip4 != nil {
+		sa4 := &unix.SockaddrInet4{}
+		copy(sa4.Addr[:], ip4)
+		sa = sa4
+	} else {
+		family, proto = unix.AF_INET6, unix.IPPROTO_ICMPV6
+		sa6 := &unix.SockaddrInet6{}
+		copy(sa6.Addr[:], src.To16())
+		sa = sa6
+	}
+
+	fd, err := unix.Socket(family, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, proto)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ICMP socket")
+	}
+	f := os.NewFile(uintptr(fd), "icmp")
+	defer func() { _ = f.Close() }()
+
+	if err = unix.BindToDevice(fd, ifName); err != nil {
+		return nil, errors.Wrapf(err, "failed to bind the ICMP socket to the interface %s", ifName)
+	}
+	if err = unix.Bind(fd, sa); err != nil {
+		return nil, errors.Wrapf(err, "failed to bind the ICMP socket to %s", src)
+	}
+	c, err := net.FilePacketConn(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ICMP connection")
+	}
+	return c, nil
+}
+
+// echo sends the ICMP echo request and waits for the reply until the timeout
+func echo(c net.PacketConn, isIPv6 bool, dst net.IP, seq int, timeout time.Duration) (bool, error) {
+	var msgType icmp.Type = ipv4.ICMPTypeEcho
+	var replyType icmp.Type = ipv4.ICMPTypeEchoReply
+	proto := protocolICMP
+	if isIPv6 {
+		msgType, replyType, proto = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply, protocolIPv6ICMP
+	}
+
+	payload := []byte("liveness-probe-" + strconv.Itoa(seq))
+	// the ID and the checksum are set by the kernel for the unprivileged ICMP sockets
+	request, err := (&icmp.Message{
+		Type: msgType,
+		Body: &icmp.Echo{Seq: seq, Data: payload},
+	}).Marshal(nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to marshal ICMP echo request")
+	}
+
+	if err = c.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return false, errors.Wrap(err, "failed to set deadline")
+	}
+	if _, err = c.WriteTo(request, &net.UDPAddr{IP: dst}); err != nil {
+		return false, nil
+	}
+
+	reply := make([]byte, 1500)
+	for {
+		n, _, err := c.ReadFrom(reply)
+		if err != nil {
+			return false, nil
+		}
+		msg, err := icmp.ParseMessage(proto, reply[:n])
+		if err != nil || msg.Type != replyType {
+			continue
+		}
+		if body, ok := msg.Body.(*icmp.Echo); ok && body.Seq == seq && bytes.Equal(body.Data, payload) {
+			return true, nil
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package heal
+
+import (
+	"syscall"
+)
+
+// bindToDevice is not supported on this platform, the sockets are not bound to the interface
+func bindToDevice(string) func(network, address string, c syscall.RawConn) error {
+	return nil
+}
+
+// newICMPPingerFactory returns the ICMP echo pinger factory, the pingers are not bound to the interface on this
+// platform
+func newICMPPingerFactory(string) PingerFactory {
+	return &defaultPingerFactory{}
+}
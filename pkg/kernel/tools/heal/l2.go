@@ -0,0 +1,113 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heal
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/ljkiraly/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+)
+
+// TargetLabel is the connection label with the comma separated IPs probed by the L2 liveness check
+const TargetLabel = "liveness-probe-targets"
+
+// WithRequiredL2Targets - makes the L2 liveness check report the connections with ethernet context and no targets
+// unhealthy. By default they are healthy and a warning is logged, since the ethernet connections often have no DstIPs.
+func WithRequiredL2Targets() Option {
+	return func(o *options) {
+		o.requireL2Targets = true
+	}
+}
+
+// KernelL2LivenessCheck is an implementation of heal.LivenessCheck for the connections without IP configured on
+// the interface, e.g. the ethernet payload connections
+func KernelL2LivenessCheck(deadlineCtx context.Context, conn *networkservice.Connection) bool {
+	return KernelL2LivenessCheckWithOptions(deadlineCtx, conn)
+}
+
+// KernelL2LivenessCheckWithOptions is an implementation with options of heal.LivenessCheck. It sends ARP requests
+// or IPv6 neighbor solicitations from the interface of the kernel mechanism to the targets and checks reply.
+// The targets are taken from the TargetLabel label of the connection or from the DstIPs of the connection, a connection
+// without targets is healthy unless WithRequiredL2Targets is set. If the DstMac of the ethernet context is set, the reply should
+// come from it. The probe set with the options is ignored.
+func KernelL2LivenessCheckWithOptions(deadlineCtx context.Context, conn *networkservice.Connection, opts ...Option) bool {
+	return KernelL2LivenessReport(deadlineCtx, conn, opts...).Healthy
+}
+
+// KernelL2LivenessReport checks the liveness of the connection like KernelL2LivenessCheckWithOptions and returns
+// the report with the statistics of every target
+func KernelL2LivenessReport(deadlineCtx context.Context, conn *networkservice.Connection, opts ...Option) *LivenessReport {
+	o := newOptions(opts...)
+
+	mechanism := kernel.ToMechanism(conn.GetMechanism())
+	if mechanism == nil || mechanism.GetInterfaceName() == "" {
+		log.FromContext(deadlineCtx).Warnf("L2 probe is not supported for mechanism %v", conn.GetMechanism())
+		return &LivenessReport{Healthy: true}
+	}
+
+	var pairs []ipPair
+	for _, target := range l2Targets(conn) {
+		pair := ipPair{dstIP: target.String()}
+		// the source IP is optional, it is used if the connection has one of the same family
+		for _, srcIPNet := range conn.GetContext().GetIpContext().GetSrcIPNets() {
+			if (srcIPNet.IP.To4() != nil) == (target.To4() != nil) {
+				pair.srcIP = srcIPNet.IP.String()
+				break
+			}
+		}
+		pairs = append(pairs, pair)
+	}
+	if len(pairs) == 0 {
+		// nothing can be probed, an ethernet connection is not known to be healthy
+		if conn.GetContext().GetEthernetContext() != nil {
+			log.FromContext(deadlineCtx).Warnf("No L2 probe targets: set the %s label or the DstIPs of the connection", TargetLabel)
+			return &LivenessReport{Healthy: !o.requireL2Targets}
+		}
+		log.FromContext(deadlineCtx).Debug("No IP address")
+		return &LivenessReport{Healthy: true}
+	}
+
+	var dstMAC net.HardwareAddr
+	if mac := conn.GetContext().GetEthernetContext().GetDstMac(); mac != "" {
+		var err error
+		if dstMAC, err = net.ParseMAC(mac); err != nil {
+			log.FromContext(deadlineCtx).Warnf("invalid destination MAC %s: %s", mac, err.Error())
+		}
+	}
+
+	return checkPairs(deadlineCtx, o, mechanism.GetNetNSURL(), newL2PingerFactory(mechanism.GetInterfaceName(), dstMAC), pairs)
+}
+
+func l2Targets(conn *networkservice.Connection) []net.IP {
+	var targets []net.IP
+	if value, ok := conn.GetLabels()[TargetLabel]; ok {
+		for _, s := range strings.Split(value, ",") {
+			if ip := net.ParseIP(strings.TrimSpace(s)); ip != nil {
+				targets = append(targets, ip)
+			}
+		}
+		return targets
+	}
+	for _, dstIPNet := range conn.GetContext().GetIpContext().GetDstIPNets() {
+		targets = append(targets, dstIPNet.IP)
+	}
+	return targets
+}
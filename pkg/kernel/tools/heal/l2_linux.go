@@ -0,0 +1,289 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package heal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	arpPacketLen   = 28
+	arpRequest     = 1
+	arpReply       = 2
+	ndpSolicit     = 135
+	ndpAdvert      = 136
+	ndpSolicitLen  = 32
+	ipv6HeaderLen  = 40
+	ndpTargetLLOpt = 2
+	ndpHopLimit    = 255
+	maxReplyLen    = 1500
+)
+
+// newL2PingerFactory returns the factory of the pingers sending ARP requests for the IPv4 targets and IPv6
+// neighbor solicitations for the IPv6 targets from the interface. If dstMAC is set the reply should come from it.
+func newL2PingerFactory(ifName string, dstMAC net.HardwareAddr) PingerFactory {
+	return &l2PingerFactory{
+		ifName: ifName,
+		dstMAC: dstMAC,
+	}
+}
+
+type l2PingerFactory struct {
+	ifName string
+	dstMAC net.HardwareAddr
+}
+
+func (f *l2PingerFactory) CreatePinger(srcIP, dstIP string, timeout time.Duration, count int) Pinger {
+	return f.createPinger(srcIP, dstIP, timeout, defaultInterval(timeout, count), count)
+}
+
+func (f *l2PingerFactory) createPinger(srcIP, dstIP string, timeout, interval time.Duration, count int) Pinger {
+	src, dst := net.ParseIP(srcIP), net.ParseIP(dstIP)
+	return newProbePinger(timeout, interval, count, func(timeout time.Duration) (bool, error) {
+		// the interface is looked up by the pinger, so it is done in the net NS of the connection
+		iface, err := net.InterfaceByName(f.ifName)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to find interface %s", f.ifName)
+		}
+		if dst.To4() != nil {
+			return f.arp(iface, src.To4(), dst.To4(), timeout)
+		}
+		return f.ndp(iface, dst, timeout)
+	})
+}
+
+func (f *l2PingerFactory) arp(iface *net.Interface, src, dst net.IP, timeout time.Duration) (bool, error) {
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, int(htons(unix.ETH_P_ARP)))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create ARP socket")
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	if err = unix.Bind(fd, &unix.SockaddrLinklayer{Protocol: htons(unix.ETH_P_ARP), Ifindex: iface.Index}); err != nil {
+		return false, errors.Wrapf(err, "failed to bind ARP socket to the interface %s", iface.Name)
+	}
+
+	if src == nil {
+		// ARP probe, RFC 5227
+		src = net.IPv4zero.To4()
+	}
+	request := make([]byte, arpPacketLen)
+	binary.BigEndian.PutUint16(request[0:2], 1)             // Ethernet
+	binary.BigEndian.PutUint16(request[2:4], unix.ETH_P_IP) // IPv4
+	request[4], request[5] = 6, 4                           // hardware and protocol address lengths
+	binary.BigEndian.PutUint16(request[6:8], arpRequest)    // operation
+	copy(request[8:14], iface.HardwareAddr)                 // sender hardware address
+	copy(request[14:18], src)                               // sender protocol address
+	copy(request[24:28], dst)                               // target protocol address
+	broadcast := &unix.SockaddrLinklayer{
+		Protocol: htons(unix.ETH_P_ARP),
+		Ifindex:  iface.Index,
+		Halen:    6,
+		Addr:     [8]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	}
+	if err = unix.Sendto(fd, request, 0, broadcast); err != nil {
+		return false, errors.Wrapf(err, "failed to send ARP request on the interface %s", iface.Name)
+	}
+
+	return recvUntil(fd, time.Now().Add(timeout), func(reply []byte) bool {
+		return len(reply) >= arpPacketLen &&
+			binary.BigEndian.Uint16(reply[6:8]) == arpReply &&
+			bytes.Equal(reply[14:18], dst) &&
+			bytes.Equal(reply[18:24], iface.HardwareAddr) &&
+			(f.dstMAC == nil || bytes.Equal(reply[8:14], f.dstMAC))
+	})
+}
+
+func (f *l2PingerFactory) ndp(iface *net.Interface, dst net.IP, timeout time.Duration) (bool, error) {
+	fd, err := unix.Socket(unix.AF_INET6, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.IPPROTO_ICMPV6)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create ICMPv6 socket")
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	if err = unix.BindToDevice(fd, iface.Name); err != nil {
+		return false, errors.Wrapf(err, "failed to bind ICMPv6 socket to the interface %s", iface.Name)
+	}
+	// only the neighbor advertisements are received
+	filter := &unix.ICMPv6Filter{}
+	for i := range filter.Data {
+		filter.Data[i] = ^uint32(0)
+	}
+	filter.Data[ndpAdvert>>5] &^= 1 << (ndpAdvert & 31)
+	if err = unix.SetsockoptICMPv6Filter(fd, unix.SOL_ICMPV6, unix.ICMPV6_FILTER, filter); err != nil {
+		return false, errors.Wrap(err, "failed to set ICMPv6 filter")
+	}
+	if err = unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_HOPS, ndpHopLimit); err != nil {
+		return false, errors.Wrap(err, "failed to set multicast hop limit")
+	}
+	if err = unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_IF, iface.Index); err != nil {
+		return false, errors.Wrap(err, "failed to set multicast interface")
+	}
+
+	request := make([]byte, ndpSolicitLen)
+	request[0] = ndpSolicit
+	copy(request[8:24], dst.To16())
+
+	// solicited-node multicast address ff02::1:ffXX:XXXX
+	solicited := net.ParseIP("ff02::1:ff00:0")
+	copy(solicited[13:], dst.To16()[13:])
+
+	// without a link-local address the kernel has no source for the solicitation, then it is sent from the
+	// unspecified address without the source link-layer address option (RFC 4861 4.3) and answered to all-nodes
+	if !hasLinkLocalIPv6(iface) {
+		if err = sendUnspecifiedSolicit(iface, solicited, request[:ndpSolicitLen-8]); err != nil {
+			return false, errors.Wrapf(err, "failed to send neighbor solicitation on the interface %s", iface.Name)
+		}
+	} else {
+		// the checksum is set by the kernel
+		request[24], request[25] = 1, 1 // source link-layer address option
+		copy(request[26:32], iface.HardwareAddr)
+		to := &unix.SockaddrInet6{ZoneId: uint32(iface.Index)}
+		copy(to.Addr[:], solicited)
+		if err = unix.Sendto(fd, request, 0, to); err != nil {
+			return false, errors.Wrapf(err, "failed to send neighbor solicitation on the interface %s", iface.Name)
+		}
+	}
+
+	return recvUntil(fd, time.Now().Add(timeout), func(reply []byte) bool {
+		if len(reply) < 24 || reply[0] != ndpAdvert || !bytes.Equal(reply[8:24], dst.To16()) {
+			return false
+		}
+		return f.dstMAC == nil || bytes.Equal(ndpTargetLLAddr(reply[24:]), f.dstMAC)
+	})
+}
+
+// sendUnspecifiedSolicit sends the neighbor solicitation from the unspecified address, the IPv6 packet is built
+// here since the kernel doesn't send it from a raw socket
+func sendUnspecifiedSolicit(iface *net.Interface, solicited net.IP, icmp []byte) error {
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, int(htons(unix.ETH_P_IPV6)))
+	if err != nil {
+		return errors.Wrap(err, "failed to create IPv6 packet socket")
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	packet := make([]byte, ipv6HeaderLen+len(icmp))
+	packet[0] = 0x60 // version
+	binary.BigEndian.PutUint16(packet[4:6], uint16(len(icmp)))
+	packet[6] = unix.IPPROTO_ICMPV6
+	packet[7] = ndpHopLimit
+	// the source address is left unspecified
+	copy(packet[24:40], solicited)
+	copy(packet[ipv6HeaderLen:], icmp)
+	binary.BigEndian.PutUint16(packet[ipv6HeaderLen+2:ipv6HeaderLen+4], icmpv6Checksum(net.IPv6unspecified, solicited, icmp))
+
+	// the multicast MAC address 33:33:ffXX:XXXX of the solicited-node address
+	to := &unix.SockaddrLinklayer{
+		Protocol: htons(unix.ETH_P_IPV6),
+		Ifindex:  iface.Index,
+		Halen:    6,
+		Addr:     [8]byte{0x33, 0x33, solicited[12], solicited[13], solicited[14], solicited[15]},
+	}
+	return unix.Sendto(fd, packet, 0, to)
+}
+
+// icmpv6Checksum returns the checksum of the ICMPv6 message with the zero checksum field, RFC 4443 2.3
+func icmpv6Checksum(src, dst net.IP, icmp []byte) uint16 {
+	pseudoHeader := make([]byte, 40)
+	copy(pseudoHeader[0:16], src.To16())
+	copy(pseudoHeader[16:32], dst.To16())
+	binary.BigEndian.PutUint32(pseudoHeader[32:36], uint32(len(icmp)))
+	pseudoHeader[39] = unix.IPPROTO_ICMPV6
+
+	var sum uint32
+	for _, b := range [][]byte{pseudoHeader, icmp} {
+		for i := 0; i < len(b); i += 2 {
+			word := uint32(b[i]) << 8
+			if i+1 < len(b) {
+				word |= uint32(b[i+1])
+			}
+			sum += word
+		}
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
+
+func hasLinkLocalIPv6(iface *net.Interface) bool {
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() == nil && ipNet.IP.IsLinkLocalUnicast() {
+			return true
+		}
+	}
+	return false
+}
+
+// ndpTargetLLAddr returns the target link-layer address option value of the neighbor advertisement
+func ndpTargetLLAddr(options []byte) []byte {
+	for len(options) >= 8 && options[1] != 0 {
+		optLen := int(options[1]) * 8
+		if optLen > len(options) {
+			break
+		}
+		if options[0] == ndpTargetLLOpt {
+			return options[2:8]
+		}
+		options = options[optLen:]
+	}
+	return nil
+}
+
+// recvUntil receives the packets from the socket until the match or the deadline
+func recvUntil(fd int, deadline time.Time, match func(reply []byte) bool) (bool, error) {
+	reply := make([]byte, maxReplyLen)
+	for {
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return false, nil
+		}
+		tv := unix.NsecToTimeval(timeout.Nanoseconds())
+		if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv); err != nil {
+			return false, errors.Wrap(err, "failed to set receive timeout")
+		}
+		n, _, err := unix.Recvfrom(fd, reply, 0)
+		if err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			return false, nil
+		}
+		if match(reply[:n]) {
+			return true, nil
+		}
+	}
+}
+
+func htons(v uint16) uint16 {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, v)
+	return binary.NativeEndian.Uint16(b)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build perm
+// +build perm
+
+package heal_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/heal"
+)
+
+const (
+	peerMAC = "02:00:00:00:00:02"
+	peerIP  = "10.0.0.2"
+)
+
+func Test_L2LivenessCheckPerm(t *testing.T) {
+	netNS := newPeerNetNS(t)
+	defer func() { _ = netNS.Close() }()
+
+	samples := []struct {
+		Name           string
+		DstIP          string
+		DstMAC         string
+		ExpectedResult bool
+	}{
+		{
+			Name:           "ARP",
+			DstIP:          peerIP,
+			ExpectedResult: true,
+		},
+		{
+			Name:           "ARP from the peer MAC",
+			DstIP:          peerIP,
+			DstMAC:         peerMAC,
+			ExpectedResult: true,
+		},
+		{
+			Name:           "ARP from another MAC",
+			DstIP:          peerIP,
+			DstMAC:         "02:00:00:00:00:03",
+			ExpectedResult: false,
+		},
+		{
+			Name:           "ARP to missing peer",
+			DstIP:          "10.0.0.3",
+			ExpectedResult: false,
+		},
+	}
+	for _, s := range samples {
+		sample := s
+		t.Run(sample.Name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+			defer cancel()
+
+			conn := createConnection(nil, []string{sample.DstIP + "/32"})
+			conn.GetMechanism().Parameters = map[string]string{
+				kernel.InterfaceNameKey: "nsm-l2",
+				kernel.NetNSURL:         fmt.Sprintf("fd://%d", netNS),
+			}
+			conn.GetContext().EthernetContext = &networkservice.EthernetContext{DstMac: sample.DstMAC}
+
+			require.Equal(t, sample.ExpectedResult, heal.KernelL2LivenessCheck(ctx, conn))
+		})
+	}
+}
+
+// newPeerNetNS creates a net NS with the veth pair nsm-l2 - nsm-l2-peer, only the peer has IP
+func newPeerNetNS(t *testing.T) netns.NsHandle {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	baseHandle, err := netns.Get()
+	require.NoError(t, err)
+	defer func() {
+		_ = netns.Set(baseHandle)
+		_ = baseHandle.Close()
+	}()
+
+	newHandle, err := netns.New()
+	require.NoError(t, err)
+
+	mac, err := net.ParseMAC(peerMAC)
+	require.NoError(t, err)
+	veth := &netlink.Veth{
+		LinkAttrs:        netlink.LinkAttrs{Name: "nsm-l2"},
+		PeerName:         "nsm-l2-peer",
+		PeerHardwareAddr: mac,
+	}
+	require.NoError(t, netlink.LinkAdd(veth))
+
+	peer, err := netlink.LinkByName(veth.PeerName)
+	require.NoError(t, err)
+	addr, err := netlink.ParseAddr(peerIP + "/24")
+	require.NoError(t, err)
+	require.NoError(t, netlink.AddrAdd(peer, addr))
+	require.NoError(t, netlink.LinkSetUp(peer))
+	require.NoError(t, netlink.LinkSetUp(veth))
+
+	return newHandle
+}
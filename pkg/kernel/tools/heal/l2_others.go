@@ -0,0 +1,44 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package heal
+
+import (
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// newL2PingerFactory returns the factory of the pingers failing with not supported error on this platform
+func newL2PingerFactory(string, net.HardwareAddr) PingerFactory {
+	return &l2PingerFactory{}
+}
+
+type l2PingerFactory struct{}
+
+func (f *l2PingerFactory) CreatePinger(srcIP, dstIP string, timeout time.Duration, count int) Pinger {
+	return f.createPinger(srcIP, dstIP, timeout, defaultInterval(timeout, count), count)
+}
+
+func (f *l2PingerFactory) createPinger(_, _ string, timeout, interval time.Duration, count int) Pinger {
+	return newProbePinger(timeout, interval, count, func(time.Duration) (bool, error) {
+		return false, errors.New("L2 probe is not supported on this platform")
+	})
+}
@@ -19,6 +19,7 @@ package heal
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -35,16 +36,67 @@ const (
 )
 
 type options struct {
-	pingerFactory PingerFactory
+	probe         probe
+	lossThreshold float64
+	rttThreshold  time.Duration
+	packetCount   int
+	interval      time.Duration
+	pairTimeout   time.Duration
+	concurrency   int
+	earlyExit     bool
+	// requireL2Targets makes the L2 check of an ethernet connection without targets fail
+	requireL2Targets bool
 }
 
 // Option is an option pattern for LivelinessChecker
 type Option func(o *options)
 
-// WithPingerFactory - sets any custom pinger factory
+// WithPingerFactory - sets any custom pinger factory. The pingers are run in the net NS of the kernel mechanism, not
+// in the forwarder net NS, so a custom factory should not rely on the forwarder interfaces or routes.
 func WithPingerFactory(pf PingerFactory) Option {
 	return func(o *options) {
-		o.pingerFactory = pf
+		o.probe = func(*networkservice.Connection) (PingerFactory, error) {
+			return pf, nil
+		}
+	}
+}
+
+// WithPacketCount - sets the number of the packets sent to every Src/DstIPs combination, 4 by default
+func WithPacketCount(count int) Option {
+	return func(o *options) {
+		o.packetCount = count
+	}
+}
+
+// WithInterval - sets the interval between the packets, by default the packets are spread over the timeout.
+// It is ignored by the custom pinger factories.
+func WithInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.interval = interval
+	}
+}
+
+// WithPairTimeout - sets the timeout of the check of every Src/DstIPs combination, by default it is the time left
+// until the deadline of the context. The check never exceeds the deadline of the context.
+func WithPairTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.pairTimeout = timeout
+	}
+}
+
+// WithConcurrency - sets the number of the Src/DstIPs combinations checked at the same time, 0 means all of them
+func WithConcurrency(concurrency int) Option {
+	return func(o *options) {
+		o.concurrency = concurrency
+	}
+}
+
+// WithEarlyExit - makes the check return on the first unhealthy Src/DstIPs combination, the combinations not
+// started yet are skipped and the ones already started are stopped. The custom pingers are stopped only if they
+// implement Stop(), the others are waited for. The combinations started are in the report.
+func WithEarlyExit() Option {
+	return func(o *options) {
+		o.earlyExit = true
 	}
 }
 
@@ -54,87 +106,183 @@ func KernelLivenessCheck(deadlineCtx context.Context, conn *networkservice.Conne
 }
 
 // KernelLivenessCheckWithOptions is an implementation with options of heal.LivenessCheck. It sends ICMP
-// ping and checks reply. Returns false if didn't get reply.
+// ping, or the probe set with the options, and checks reply. Returns false if didn't get reply. The probes are run
+// in the net NS of the kernel mechanism and are bound to the interface of the mechanism.
 func KernelLivenessCheckWithOptions(deadlineCtx context.Context, conn *networkservice.Connection, opts ...Option) bool {
-	// Apply options
-	o := &options{
-		pingerFactory: &defaultPingerFactory{},
-	}
-	for _, opt := range opts {
-		opt(o)
-	}
-	var pingerFactory = o.pingerFactory
+	return KernelLivenessReport(deadlineCtx, conn, opts...).Healthy
+}
 
-	if mechanism := conn.GetMechanism().GetType(); mechanism != kernel.MECHANISM {
-		log.FromContext(deadlineCtx).Warnf("ping is not supported for mechanism %v", mechanism)
-		return true
+// KernelLivenessReport checks the liveness of the connection like KernelLivenessCheckWithOptions and returns the
+// report with the statistics of every Src/DstIPs combination
+func KernelLivenessReport(deadlineCtx context.Context, conn *networkservice.Connection, opts ...Option) *LivenessReport {
+	o := newOptions(opts...)
+
+	mechanism := kernel.ToMechanism(conn.GetMechanism())
+	if mechanism == nil {
+		log.FromContext(deadlineCtx).Warnf("ping is not supported for mechanism %v", conn.GetMechanism().GetType())
+		return &LivenessReport{Healthy: true}
 	}
 	ipContext := conn.GetContext().GetIpContext()
 	combinationCount := len(ipContext.GetDstIpAddrs()) * len(ipContext.GetSrcIpAddrs())
 	if combinationCount == 0 {
 		log.FromContext(deadlineCtx).Debug("No IP address")
-		return true
+		return &LivenessReport{Healthy: true}
 	}
 
-	deadline, ok := deadlineCtx.Deadline()
-	if !ok {
-		deadline = time.Now().Add(defaultTimeout)
+	pingerFactory, err := o.probe(conn)
+	if err != nil {
+		log.FromContext(deadlineCtx).Warnf("%s: falling back to ICMP ping", err.Error())
+		pingerFactory = newICMPPingerFactory(interfaceName(conn))
 	}
-	timeout := time.Until(deadline)
 
-	// Start ping for all Src/DstIPs combination
-	responseCh := make(chan error, combinationCount)
-	defer close(responseCh)
+	var pairs []ipPair
 	for _, srcIPNet := range ipContext.GetSrcIPNets() {
 		for _, dstIPNet := range ipContext.GetDstIPNets() {
 			// Skip if IPs don't belong to the same family
 			if (srcIPNet.IP.To4() != nil) != (dstIPNet.IP.To4() != nil) {
-				responseCh <- nil
 				continue
 			}
+			pairs = append(pairs, ipPair{srcIP: srcIPNet.IP.String(), dstIP: dstIPNet.IP.String()})
+		}
+	}
 
-			go func(srcIP, dstIP string) {
-				logger := log.FromContext(deadlineCtx).WithField("srcIP", srcIP).WithField("dstIP", dstIP)
-				pinger := pingerFactory.CreatePinger(srcIP, dstIP, timeout, packetCount)
+	return checkPairs(deadlineCtx, o, mechanism.GetNetNSURL(), pingerFactory, pairs)
+}
 
-				err := pinger.Run()
-				if err != nil {
-					logger.Errorf("Ping failed: %s", err.Error())
-					responseCh <- err
-					return
-				}
+func newOptions(opts ...Option) *options {
+	o := &options{
+		lossThreshold: 100,
+		packetCount:   packetCount,
+	}
+	WithICMPProbe()(o)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
 
-				if pinger.GetReceivedPackets() == 0 {
-					err = errors.New("No packets received")
-					logger.Errorf(err.Error())
-					responseCh <- err
-					return
+type ipPair struct {
+	srcIP, dstIP string
+}
+
+// checkPairs runs the pingers for all the pairs in the net NS and waits for the results. On early exit the pingers
+// already started are stopped and waited for, so no probe outlives the check.
+func checkPairs(deadlineCtx context.Context, o *options, netNSURL string, pingerFactory PingerFactory, pairs []ipPair) *LivenessReport {
+	deadline, ok := deadlineCtx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+
+	concurrency := o.concurrency
+	if concurrency <= 0 || concurrency > len(pairs) {
+		concurrency = len(pairs)
+	}
+
+	ctx, cancel := context.WithCancel(deadlineCtx)
+	defer cancel()
+
+	runIn, closeNetNS, err := netNSRunner(ctx, netNSURL, concurrency)
+	if err != nil {
+		log.FromContext(deadlineCtx).Errorf("Ping failed: %s", err.Error())
+		return &LivenessReport{Err: err}
+	}
+	defer closeNetNS()
+
+	// Start ping for all Src/DstIPs combination, at most concurrency of them at the same time
+	responseCh := make(chan *PairReport, len(pairs))
+	stopCh := make(chan struct{})
+	var stopOnce sync.Once
+	stop := func() { stopOnce.Do(func() { close(stopCh) }) }
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		semaphore := make(chan struct{}, concurrency)
+		for _, pair := range pairs {
+			select {
+			case <-stopCh:
+				return
+			case semaphore <- struct{}{}:
+			}
+			// no combination is started after an early exit
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+			wg.Add(1)
+			go func(pair ipPair) {
+				defer wg.Done()
+				pairReport := checkPair(ctx, o, runIn, pingerFactory, pair, deadline)
+				if o.earlyExit && !pairReport.Healthy {
+					stop()
 				}
-				responseCh <- nil
-			}(srcIPNet.IP.String(), dstIPNet.IP.String())
+				responseCh <- pairReport
+				<-semaphore
+			}(pair)
 		}
-	}
+	}()
+
+	// Waiting for all ping results. If at least one fails - the connection is unhealthy
+	report := waitForResponses(responseCh, len(pairs), o.earlyExit)
+	stop()
+	cancel()
+	wg.Wait()
 
-	// Waiting for all ping results. If at least one fails - return false
-	return waitForResponses(responseCh)
+	// the results of the pingers stopped on early exit
+	close(responseCh)
+	for pair := range responseCh {
+		report.Pairs = append(report.Pairs, pair)
+	}
+	return report
 }
 
-func waitForResponses(responseCh <-chan error) bool {
-	respCount := cap(responseCh)
-	success := true
-	for {
-		resp, ok := <-responseCh
-		if !ok {
-			return false
+func checkPair(ctx context.Context, o *options, runIn func(runner func() error) error, pingerFactory PingerFactory, pair ipPair, deadline time.Time) *PairReport {
+	logger := log.FromContext(ctx).WithField("srcIP", pair.srcIP).WithField("dstIP", pair.dstIP)
+
+	timeout := time.Until(deadline)
+	if o.pairTimeout > 0 && o.pairTimeout < timeout {
+		timeout = o.pairTimeout
+	}
+
+	var report *PairReport
+	if timeout <= 0 {
+		report = &PairReport{SrcIP: pair.srcIP, DstIP: pair.dstIP, Loss: 100, Err: errors.New("deadline exceeded")}
+	} else {
+		var pinger Pinger
+		if f, ok := pingerFactory.(intervalPingerFactory); ok && o.interval > 0 {
+			pinger = f.createPinger(pair.srcIP, pair.dstIP, timeout, o.interval, o.packetCount)
+		} else {
+			pinger = pingerFactory.CreatePinger(pair.srcIP, pair.dstIP, timeout, o.packetCount)
 		}
-		if resp != nil {
-			success = false
+		if stoppable, ok := pinger.(stoppablePinger); ok {
+			defer context.AfterFunc(ctx, stoppable.Stop)()
 		}
-		respCount--
-		if respCount == 0 {
-			return success
+		report = newPairReport(pair.srcIP, pair.dstIP, pinger, o.packetCount, runIn(pinger.Run))
+	}
+
+	report.evaluate(o)
+	if !report.Healthy {
+		logger.Errorf("Ping failed: %s", report.Err.Error())
+	} else {
+		logger.Debug(report.String())
+	}
+	return report
+}
+
+func waitForResponses(responseCh <-chan *PairReport, count int, earlyExit bool) *LivenessReport {
+	report := &LivenessReport{
+		Healthy: true,
+	}
+	for ; count > 0; count-- {
+		pair := <-responseCh
+		report.Pairs = append(report.Pairs, pair)
+		report.Healthy = report.Healthy && pair.Healthy
+		if earlyExit && !pair.Healthy {
+			break
 		}
 	}
+	return report
 }
 
 // PingerFactory - factory interface for creating pingers
@@ -151,17 +299,37 @@ type Pinger interface {
 type defaultPingerFactory struct{}
 
 func (p *defaultPingerFactory) CreatePinger(srcIP, dstIP string, timeout time.Duration, count int) Pinger {
+	return p.createPinger(srcIP, dstIP, timeout, defaultInterval(timeout, count), count)
+}
+
+func (p *defaultPingerFactory) createPinger(srcIP, dstIP string, timeout, interval time.Duration, count int) Pinger {
 	pi := ping.New(dstIP)
 	pi.Source = srcIP
 	pi.Timeout = timeout
 	pi.Count = count
-	if count != 0 {
-		pi.Interval = timeout / time.Duration(count)
-	}
+	pi.Interval = interval
 
 	return &defaultPinger{pinger: pi}
 }
 
+// stoppablePinger is the pinger which can be stopped before it is done
+type stoppablePinger interface {
+	Stop()
+}
+
+// intervalPingerFactory is the pinger factory supporting the interval between the packets
+type intervalPingerFactory interface {
+	createPinger(srcIP, dstIP string, timeout, interval time.Duration, count int) Pinger
+}
+
+// defaultInterval returns the interval spreading the packets over the timeout
+func defaultInterval(timeout time.Duration, count int) time.Duration {
+	if count == 0 {
+		return timeout
+	}
+	return timeout / time.Duration(count)
+}
+
 type defaultPinger struct {
 	pinger *ping.Pinger
 }
@@ -170,6 +338,21 @@ func (p *defaultPinger) Run() error {
 	return p.pinger.Run()
 }
 
+func (p *defaultPinger) Stop() {
+	p.pinger.Stop()
+}
+
 func (p *defaultPinger) GetReceivedPackets() int {
 	return p.pinger.Statistics().PacketsRecv
 }
+
+func (p *defaultPinger) GetStatistics() *Statistics {
+	stats := p.pinger.Statistics()
+	return &Statistics{
+		Sent:     stats.PacketsSent,
+		Received: stats.PacketsRecv,
+		MinRTT:   stats.MinRtt,
+		AvgRTT:   stats.AvgRtt,
+		MaxRTT:   stats.MaxRtt,
+	}
+}
@@ -18,6 +18,8 @@ package heal_test
 
 import (
 	"context"
+	"net"
+	"strconv"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -158,3 +160,357 @@ func (p *testPinger) GetReceivedPackets() int {
 	}
 	return p.count
 }
+
+func Test_LivenessChecker_Probes(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = tcpListener.Close() }()
+	go func() {
+		for {
+			c, acceptErr := tcpListener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			_ = c.Close()
+		}
+	}()
+	tcpPort := uint16(tcpListener.Addr().(*net.TCPAddr).Port)
+
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = udpConn.Close() }()
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, readErr := udpConn.ReadFrom(buf)
+			if readErr != nil {
+				return
+			}
+			_, _ = udpConn.WriteTo(buf[:n], addr)
+		}
+	}()
+	udpPort := uint16(udpConn.LocalAddr().(*net.UDPAddr).Port)
+
+	samples := []struct {
+		Name           string
+		Labels         map[string]string
+		Policies       []*networkservice.PolicyRoute
+		Parameters     map[string]string
+		Option         heal.Option
+		ExpectedResult bool
+	}{
+		{
+			Name:           "TCP probe",
+			Option:         heal.WithTCPProbe(tcpPort),
+			ExpectedResult: true,
+		},
+		{
+			Name:           "TCP probe port from label",
+			Labels:         map[string]string{heal.PortLabel: strconv.Itoa(int(tcpPort))},
+			Option:         heal.WithTCPProbe(0),
+			ExpectedResult: true,
+		},
+		{
+			Name:           "UDP probe",
+			Option:         heal.WithUDPProbe(udpPort),
+			ExpectedResult: true,
+		},
+		{
+			Name:           "UDP probe port from policy",
+			Policies:       []*networkservice.PolicyRoute{{Proto: "17", DstPort: strconv.Itoa(int(udpPort))}},
+			Option:         heal.WithUDPProbe(0),
+			ExpectedResult: true,
+		},
+		{
+			Name:           "TCP probe bound to the interface in the net NS",
+			Parameters:     map[string]string{kernel.InterfaceNameKey: "lo", kernel.NetNSURL: "file:///proc/self/ns/net"},
+			Option:         heal.WithTCPProbe(tcpPort),
+			ExpectedResult: true,
+		},
+		{
+			Name:           "UDP probe bound to the interface",
+			Parameters:     map[string]string{kernel.InterfaceNameKey: "lo"},
+			Option:         heal.WithUDPProbe(udpPort),
+			ExpectedResult: true,
+		},
+		{
+			Name:           "TCP probe bound to missing interface",
+			Parameters:     map[string]string{kernel.InterfaceNameKey: "nsm-missing0"},
+			Option:         heal.WithTCPProbe(tcpPort),
+			ExpectedResult: false,
+		},
+		{
+			Name:           "Missing net NS",
+			Parameters:     map[string]string{kernel.NetNSURL: "file:///proc/0/ns/net"},
+			Option:         heal.WithTCPProbe(tcpPort),
+			ExpectedResult: false,
+		},
+		{
+			Name:           "UDP probe without echo",
+			Option:         heal.WithUDPProbe(tcpPort),
+			ExpectedResult: false,
+		},
+	}
+	for _, s := range samples {
+		sample := s
+		t.Run(sample.Name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+			defer cancel()
+			conn := createConnection([]string{"127.0.0.1/32"}, []string{"127.0.0.1/32"})
+			conn.Labels = sample.Labels
+			conn.GetMechanism().Parameters = sample.Parameters
+			conn.GetContext().GetIpContext().Policies = sample.Policies
+			ok := heal.KernelLivenessCheckWithOptions(ctx, conn, sample.Option)
+			require.Equal(t, sample.ExpectedResult, ok)
+		})
+	}
+}
+
+func Test_LivenessReport(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	stats := heal.Statistics{
+		Sent:     4,
+		Received: 3,
+		MinRTT:   5 * time.Millisecond,
+		AvgRTT:   10 * time.Millisecond,
+		MaxRTT:   20 * time.Millisecond,
+	}
+
+	samples := []struct {
+		Name           string
+		Options        []heal.Option
+		ExpectedResult bool
+	}{
+		{
+			Name:           "Default thresholds",
+			ExpectedResult: true,
+		},
+		{
+			Name:           "Loss threshold reached",
+			Options:        []heal.Option{heal.WithLossThreshold(25)},
+			ExpectedResult: false,
+		},
+		{
+			Name:           "Loss threshold not reached",
+			Options:        []heal.Option{heal.WithLossThreshold(30)},
+			ExpectedResult: true,
+		},
+		{
+			Name:           "RTT threshold exceeded",
+			Options:        []heal.Option{heal.WithRTTThreshold(5 * time.Millisecond)},
+			ExpectedResult: false,
+		},
+	}
+	for _, s := range samples {
+		sample := s
+		t.Run(sample.Name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			conn := createConnection(
+				[]string{"172.168.0.1/32", "2004::1/128"},
+				[]string{"172.168.0.2/32", "2004::2/128"},
+			)
+			opts := append([]heal.Option{heal.WithPingerFactory(&statisticsPingerFactory{stats: stats})}, sample.Options...)
+
+			report := heal.KernelLivenessReport(ctx, conn, opts...)
+			require.Equal(t, sample.ExpectedResult, report.Healthy)
+			require.NoError(t, report.Err)
+			require.Len(t, report.Pairs, 2)
+			for _, pair := range report.Pairs {
+				require.Equal(t, stats, pair.Statistics)
+				require.InDelta(t, 25, pair.Loss, 0.001)
+				require.Equal(t, sample.ExpectedResult, pair.Healthy)
+			}
+			require.Equal(t, sample.ExpectedResult, heal.KernelLivenessCheckWithOptions(ctx, conn, opts...))
+		})
+	}
+}
+
+type statisticsPingerFactory struct {
+	stats heal.Statistics
+}
+
+func (p *statisticsPingerFactory) CreatePinger(_, _ string, _ time.Duration, _ int) heal.Pinger {
+	return &statisticsPinger{stats: p.stats}
+}
+
+type statisticsPinger struct {
+	stats heal.Statistics
+}
+
+func (p *statisticsPinger) Run() error {
+	return nil
+}
+
+func (p *statisticsPinger) GetReceivedPackets() int {
+	return p.stats.Received
+}
+
+func (p *statisticsPinger) GetStatistics() *heal.Statistics {
+	stats := p.stats
+	return &stats
+}
+
+func Test_LivenessChecker_Options(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	conn := createConnection(
+		[]string{"172.168.0.1/32", "172.168.0.3/32", "172.168.0.5/32"},
+		[]string{unPingableIPv4 + "/32", "172.168.0.2/32", "172.168.0.4/32"},
+	)
+
+	samples := []struct {
+		Name            string
+		Options         []heal.Option
+		ExpectedCount   int
+		ExpectedRunning int32
+		ExpectedPairs   int
+	}{
+		{
+			Name:            "Default options",
+			ExpectedCount:   4,
+			ExpectedRunning: 9,
+			ExpectedPairs:   9,
+		},
+		{
+			Name:            "Packet count",
+			Options:         []heal.Option{heal.WithPacketCount(2)},
+			ExpectedCount:   2,
+			ExpectedRunning: 9,
+			ExpectedPairs:   9,
+		},
+		{
+			Name:            "Concurrency",
+			Options:         []heal.Option{heal.WithConcurrency(2)},
+			ExpectedCount:   4,
+			ExpectedRunning: 2,
+			ExpectedPairs:   9,
+		},
+		{
+			Name:            "Early exit",
+			Options:         []heal.Option{heal.WithConcurrency(1), heal.WithEarlyExit()},
+			ExpectedCount:   4,
+			ExpectedRunning: 1,
+			ExpectedPairs:   1,
+		},
+	}
+	for _, s := range samples {
+		sample := s
+		t.Run(sample.Name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			pingerFactory := &concurrencyPingerFactory{}
+			opts := append([]heal.Option{heal.WithPingerFactory(pingerFactory)}, sample.Options...)
+
+			report := heal.KernelLivenessReport(ctx, conn, opts...)
+			require.False(t, report.Healthy)
+			require.Len(t, report.Pairs, sample.ExpectedPairs)
+			for _, pair := range report.Pairs {
+				require.Equal(t, sample.ExpectedCount, pair.Sent)
+			}
+			require.Equal(t, sample.ExpectedRunning, atomic.LoadInt32(&pingerFactory.maxRunning))
+		})
+	}
+}
+
+type concurrencyPingerFactory struct {
+	running    int32
+	maxRunning int32
+}
+
+func (p *concurrencyPingerFactory) CreatePinger(_, dstIP string, _ time.Duration, count int) heal.Pinger {
+	return &concurrencyPinger{
+		testPinger: testPinger{dstIP: dstIP, count: count},
+		factory:    p,
+	}
+}
+
+type concurrencyPinger struct {
+	testPinger
+	factory *concurrencyPingerFactory
+}
+
+func (p *concurrencyPinger) Run() error {
+	running := atomic.AddInt32(&p.factory.running, 1)
+	defer atomic.AddInt32(&p.factory.running, -1)
+	for {
+		maxRunning := atomic.LoadInt32(&p.factory.maxRunning)
+		if running <= maxRunning || atomic.CompareAndSwapInt32(&p.factory.maxRunning, maxRunning, running) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	return nil
+}
+
+func Test_LivenessChecker_EarlyExitStopsPingers(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	conn := createConnection(
+		[]string{"172.168.0.1/32"},
+		[]string{unPingableIPv4 + "/32", "172.168.0.2/32", "172.168.0.4/32"},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pingerFactory := &stoppablePingerFactory{}
+
+	start := time.Now()
+	report := heal.KernelLivenessReport(ctx, conn, heal.WithPingerFactory(pingerFactory), heal.WithEarlyExit())
+	require.Less(t, time.Since(start), time.Second)
+	require.False(t, report.Healthy)
+
+	// the pingers started are stopped and reported
+	require.Len(t, report.Pairs, 3)
+	require.Equal(t, int32(0), atomic.LoadInt32(&pingerFactory.running))
+}
+
+type stoppablePingerFactory struct {
+	running int32
+}
+
+func (p *stoppablePingerFactory) CreatePinger(_, dstIP string, _ time.Duration, count int) heal.Pinger {
+	return &stoppablePinger{
+		testPinger: testPinger{dstIP: dstIP, count: count},
+		factory:    p,
+		stop:       make(chan struct{}),
+	}
+}
+
+// stoppablePinger fails at once for the unpingable IP and runs until it is stopped for the others
+type stoppablePinger struct {
+	testPinger
+	factory *stoppablePingerFactory
+	stop    chan struct{}
+}
+
+func (p *stoppablePinger) Run() error {
+	if p.dstIP == unPingableIPv4 {
+		return nil
+	}
+	atomic.AddInt32(&p.factory.running, 1)
+	defer atomic.AddInt32(&p.factory.running, -1)
+	<-p.stop
+	return nil
+}
+
+func (p *stoppablePinger) Stop() {
+	close(p.stop)
+}
+
+func Test_L2LivenessCheckNoTargets(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	conn := createConnection(nil, nil)
+	conn.GetMechanism().Parameters = map[string]string{
+		kernel.InterfaceNameKey: "nsm-l2",
+	}
+	require.True(t, heal.KernelL2LivenessCheck(context.Background(), conn))
+
+	conn.GetContext().EthernetContext = &networkservice.EthernetContext{}
+	require.True(t, heal.KernelL2LivenessCheck(context.Background(), conn))
+	require.False(t, heal.KernelL2LivenessCheckWithOptions(context.Background(), conn, heal.WithRequiredL2Targets()))
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heal
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/pkg/errors"
+)
+
+// PortLabel is the connection label with the destination port of the TCP and UDP probes
+const PortLabel = "liveness-probe-port"
+
+const (
+	tcpProtocol = 6
+	udpProtocol = 17
+)
+
+// probe creates the pinger factory for the connection
+type probe func(conn *networkservice.Connection) (PingerFactory, error)
+
+// WithICMPProbe - sets the ICMP echo probe, it is the default one
+func WithICMPProbe() Option {
+	return func(o *options) {
+		o.probe = func(conn *networkservice.Connection) (PingerFactory, error) {
+			return newICMPPingerFactory(interfaceName(conn)), nil
+		}
+	}
+}
+
+// WithTCPProbe - sets the TCP connect probe. A reply is either an established connection or a connection reset,
+// both of them prove that the peer is reachable. If the port is 0 it is taken from the PortLabel label of the
+// connection or from the destination port of the first TCP policy of the connection.
+func WithTCPProbe(port uint16) Option {
+	return func(o *options) {
+		o.probe = func(conn *networkservice.Connection) (PingerFactory, error) {
+			p, err := probePort(conn, tcpProtocol, port)
+			if err != nil {
+				return nil, err
+			}
+			return &tcpPingerFactory{port: p, ifName: interfaceName(conn)}, nil
+		}
+	}
+}
+
+// WithUDPProbe - sets the UDP echo probe, the peer is expected to send the datagram back. If the port is 0 it is
+// taken from the PortLabel label of the connection or from the destination port of the first UDP policy of the
+// connection.
+func WithUDPProbe(port uint16) Option {
+	return func(o *options) {
+		o.probe = func(conn *networkservice.Connection) (PingerFactory, error) {
+			p, err := probePort(conn, udpProtocol, port)
+			if err != nil {
+				return nil, err
+			}
+			return &udpPingerFactory{port: p, ifName: interfaceName(conn)}, nil
+		}
+	}
+}
+
+func interfaceName(conn *networkservice.Connection) string {
+	return kernel.ToMechanism(conn.GetMechanism()).GetInterfaceName()
+}
+
+func probePort(conn *networkservice.Connection, protocol int, port uint16) (uint16, error) {
+	if port != 0 {
+		return port, nil
+	}
+	if value, ok := conn.GetLabels()[PortLabel]; ok {
+		p, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid %s label: %s", PortLabel, value)
+		}
+		return uint16(p), nil
+	}
+	for _, policy := range conn.GetContext().GetIpContext().GetPolicies() {
+		if policy.GetProto() != strconv.Itoa(protocol) {
+			continue
+		}
+		if portRange, err := networkservice.ParsePortRange(policy.GetDstPort()); err == nil && portRange != nil {
+			return portRange.Start, nil
+		}
+	}
+	return 0, errors.Errorf("no probe port found for the IP protocol %d", protocol)
+}
+
+// probePinger sends count probes with the interval in between until the timeout, the probe returns true if a reply
+// is received
+type probePinger struct {
+	timeout  time.Duration
+	interval time.Duration
+	count    int
+	probe    func(timeout time.Duration) (bool, error)
+	stats    Statistics
+	totalRTT time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newProbePinger(timeout, interval time.Duration, count int, probe func(timeout time.Duration) (bool, error)) *probePinger {
+	return &probePinger{
+		timeout:  timeout,
+		interval: interval,
+		count:    count,
+		probe:    probe,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (p *probePinger) Run() error {
+	deadline := time.Now().Add(p.timeout)
+	for i := 0; i < p.count; i++ {
+		start := time.Now()
+		timeout := p.interval
+		if remaining := deadline.Sub(start); remaining < timeout {
+			timeout = remaining
+		}
+		if timeout <= 0 || p.stopped() {
+			return nil
+		}
+
+		p.stats.Sent++
+		ok, err := p.probe(timeout)
+		if err != nil {
+			return err
+		}
+		if ok {
+			p.received(time.Since(start))
+		}
+		if i < p.count-1 {
+			select {
+			case <-p.stop:
+				return nil
+			case <-time.After(time.Until(start.Add(p.interval))):
+			}
+		}
+	}
+	return nil
+}
+
+// Stop makes Run return once the probe in progress is done
+func (p *probePinger) Stop() {
+	p.stopOnce.Do(func() { close(p.stop) })
+}
+
+func (p *probePinger) stopped() bool {
+	select {
+	case <-p.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *probePinger) received(rtt time.Duration) {
+	if p.stats.Received == 0 || rtt < p.stats.MinRTT {
+		p.stats.MinRTT = rtt
+	}
+	if rtt > p.stats.MaxRTT {
+		p.stats.MaxRTT = rtt
+	}
+	p.stats.Received++
+	p.totalRTT += rtt
+	p.stats.AvgRTT = p.totalRTT / time.Duration(p.stats.Received)
+}
+
+func (p *probePinger) GetReceivedPackets() int {
+	return p.stats.Received
+}
+
+func (p *probePinger) GetStatistics() *Statistics {
+	stats := p.stats
+	return &stats
+}
+
+type tcpPingerFactory struct {
+	port   uint16
+	ifName string
+}
+
+func (f *tcpPingerFactory) CreatePinger(srcIP, dstIP string, timeout time.Duration, count int) Pinger {
+	return f.createPinger(srcIP, dstIP, timeout, defaultInterval(timeout, count), count)
+}
+
+func (f *tcpPingerFactory) createPinger(srcIP, dstIP string, timeout, interval time.Duration, count int) Pinger {
+	dialer := &net.Dialer{
+		LocalAddr: &net.TCPAddr{IP: net.ParseIP(srcIP)},
+		Control:   bindToDevice(f.ifName),
+	}
+	address := net.JoinHostPort(dstIP, strconv.Itoa(int(f.port)))
+	return newProbePinger(timeout, interval, count, func(timeout time.Duration) (bool, error) {
+		dialer.Timeout = timeout
+		c, err := dialer.Dial("tcp", address)
+		if err != nil {
+			return errors.Is(err, syscall.ECONNREFUSED), nil
+		}
+		_ = c.Close()
+		return true, nil
+	})
+}
+
+type udpPingerFactory struct {
+	port   uint16
+	ifName string
+}
+
+func (f *udpPingerFactory) CreatePinger(srcIP, dstIP string, timeout time.Duration, count int) Pinger {
+	return f.createPinger(srcIP, dstIP, timeout, defaultInterval(timeout, count), count)
+}
+
+func (f *udpPingerFactory) createPinger(srcIP, dstIP string, timeout, interval time.Duration, count int) Pinger {
+	dialer := &net.Dialer{
+		LocalAddr: &net.UDPAddr{IP: net.ParseIP(srcIP)},
+		Control:   bindToDevice(f.ifName),
+	}
+	address := net.JoinHostPort(dstIP, strconv.Itoa(int(f.port)))
+	seq := 0
+	return newProbePinger(timeout, interval, count, func(timeout time.Duration) (bool, error) {
+		c, err := dialer.Dial("udp", address)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to dial %s from %s", address, srcIP)
+		}
+		defer func() { _ = c.Close() }()
+
+		seq++
+		payload := []byte("liveness-probe-" + strconv.Itoa(seq))
+		if err = c.SetDeadline(time.Now().Add(timeout)); err != nil {
+			return false, errors.Wrap(err, "failed to set deadline")
+		}
+		if _, err = c.Write(payload); err != nil {
+			return false, nil
+		}
+		reply := make([]byte, len(payload))
+		n, err := c.Read(reply)
+		if err != nil {
+			return false, nil
+		}
+		return bytes.Equal(reply[:n], payload), nil
+	})
+}
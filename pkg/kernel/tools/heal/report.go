@@ -0,0 +1,107 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LivenessReport is the result of the liveness check of a connection
+type LivenessReport struct {
+	// Healthy is false if some of the pairs is unhealthy or the check failed
+	Healthy bool
+	// Pairs are the reports of the checked Src/DstIPs combinations
+	Pairs []*PairReport
+	// Err is the error preventing the check of the pairs
+	Err error
+}
+
+// PairReport is the result of the liveness check of a Src/DstIPs combination
+type PairReport struct {
+	SrcIP, DstIP string
+	Statistics
+	// Loss is the packet loss in percents
+	Loss    float64
+	Healthy bool
+	Err     error
+}
+
+func (r *PairReport) String() string {
+	return fmt.Sprintf("%s -> %s: sent %d, received %d, loss %.1f%%, rtt min/avg/max %v/%v/%v",
+		r.SrcIP, r.DstIP, r.Sent, r.Received, r.Loss, r.MinRTT, r.AvgRTT, r.MaxRTT)
+}
+
+// Statistics - statistics of the pinger run
+type Statistics struct {
+	Sent, Received         int
+	MinRTT, AvgRTT, MaxRTT time.Duration
+}
+
+// StatisticsPinger - pinger providing the statistics of the run, the statistics of the other pingers contain only
+// the packet counts
+type StatisticsPinger interface {
+	Pinger
+	GetStatistics() *Statistics
+}
+
+// WithLossThreshold - sets the packet loss in percents which makes the connection unhealthy, 100 by default
+func WithLossThreshold(percent float64) Option {
+	return func(o *options) {
+		o.lossThreshold = percent
+	}
+}
+
+// WithRTTThreshold - sets the average RTT exceeding of which makes the connection unhealthy, 0 means no limit
+func WithRTTThreshold(rtt time.Duration) Option {
+	return func(o *options) {
+		o.rttThreshold = rtt
+	}
+}
+
+func newPairReport(srcIP, dstIP string, pinger Pinger, count int, err error) *PairReport {
+	r := &PairReport{
+		SrcIP: srcIP,
+		DstIP: dstIP,
+		Err:   err,
+	}
+	if statisticsPinger, ok := pinger.(StatisticsPinger); ok {
+		r.Statistics = *statisticsPinger.GetStatistics()
+	} else {
+		r.Sent, r.Received = count, pinger.GetReceivedPackets()
+	}
+	r.Loss = 100
+	if r.Sent > 0 {
+		r.Loss = 100 * float64(r.Sent-r.Received) / float64(r.Sent)
+	}
+	return r
+}
+
+func (r *PairReport) evaluate(o *options) {
+	switch {
+	case r.Err != nil:
+	case r.Received == 0:
+		r.Err = errors.New("No packets received")
+	case r.Loss >= o.lossThreshold:
+		r.Err = errors.Errorf("packet loss %.1f%% reached the threshold %.1f%%", r.Loss, o.lossThreshold)
+	case o.rttThreshold > 0 && r.AvgRTT > o.rttThreshold:
+		r.Err = errors.Errorf("average RTT %v exceeded the threshold %v", r.AvgRTT, o.rttThreshold)
+	}
+	r.Healthy = r.Err == nil
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package heal
+
+import (
+	"context"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// netNSRunner returns the function running the runner in the net NS of the URL on the workers threads parked there,
+// if the URL is empty the runner is run in the current net NS. The returned close function stops the threads.
+func netNSRunner(ctx context.Context, netNSURL string, workers int) (runIn func(runner func() error) error, closeFunc func(), err error) {
+	if netNSURL == "" {
+		return func(runner func() error) error { return runner() }, func() {}, nil
+	}
+
+	target, err := nshandle.FromURL(netNSURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = target.Close() }()
+
+	executor, err := nshandle.NewExecutor(target, workers)
+	if err != nil {
+		return nil, nil, err
+	}
+	runIn = func(runner func() error) error {
+		return executor.Run(ctx, runner)
+	}
+	return runIn, func() { _ = executor.Close() }, nil
+}
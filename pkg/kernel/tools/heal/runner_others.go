@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package heal
+
+import (
+	"context"
+
+	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// netNSRunner returns the function running the runner in the net NS of the URL, if the URL is empty the runner is
+// run in the current net NS
+func netNSRunner(_ context.Context, netNSURL string, _ int) (runIn func(runner func() error) error, closeFunc func(), err error) {
+	if netNSURL == "" {
+		return func(runner func() error) error { return runner() }, func() {}, nil
+	}
+
+	current, err := nshandle.Current()
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := nshandle.FromURL(netNSURL)
+	if err != nil {
+		_ = current.Close()
+		return nil, nil, err
+	}
+
+	runIn = func(runner func() error) error {
+		return nshandle.RunIn(current, target, runner)
+	}
+	closeFunc = func() {
+		_ = target.Close()
+		_ = current.Close()
+	}
+	return runIn, closeFunc, nil
+}
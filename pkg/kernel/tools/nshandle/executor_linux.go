@@ -0,0 +1,196 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package nshandle
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+type task struct {
+	runner func() error
+	result chan *runResult
+}
+
+// Executor runs functions in a net NS on a pool of OS threads permanently parked there. A worker thread is never
+// returned to the Go runtime: it is terminated when the executor is closed or when it can't be kept in the net NS.
+type Executor struct {
+	target netns.NsHandle
+	tasks  chan *task
+	done   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+
+	mu      sync.Mutex
+	workers int
+	// gone is closed when no workers are left
+	gone chan struct{}
+	// err is the error of the last failed worker replacement
+	err error
+}
+
+// NewExecutor - starts the workers OS threads parked in the target net NS. The target handle is duplicated, so
+// the caller can close it.
+func NewExecutor(target netns.NsHandle, workers int) (*Executor, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	fd, err := unix.Dup(int(target))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to duplicate net NS handle: %v", target)
+	}
+	e := &Executor{
+		target:  netns.NsHandle(fd),
+		tasks:   make(chan *task),
+		done:    make(chan struct{}),
+		workers: workers,
+		gone:    make(chan struct{}),
+	}
+
+	ready := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		e.wg.Add(1)
+		go e.work(ready)
+	}
+	for i := 0; i < workers; i++ {
+		if err = <-ready; err != nil {
+			_ = e.Close()
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Run runs runner in the target net NS on one of the worker threads. It fails if no worker threads are left, a runner
+// panic is re-raised on the caller's goroutine.
+func (e *Executor) Run(ctx context.Context, runner func() error) error {
+	t := &task{
+		runner: runner,
+		result: make(chan *runResult, 1),
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-e.done:
+		return errors.New("executor is closed")
+	case <-e.gone:
+		return errors.Wrap(e.Err(), "no executor workers left")
+	case e.tasks <- t:
+	}
+	return (<-t.result).get()
+}
+
+// Err returns the error of the last failed worker replacement, the pool has shrunk if it is set
+func (e *Executor) Err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// Close stops the workers and terminates their threads
+func (e *Executor) Close() error {
+	e.once.Do(func() {
+		close(e.done)
+		e.wg.Wait()
+		_ = e.target.Close()
+	})
+	return nil
+}
+
+func (e *Executor) work(ready chan<- error) {
+	defer e.wg.Done()
+
+	// The thread is never unlocked, so it is terminated when the goroutine returns
+	runtime.LockOSThread()
+
+	origin, err := netns.Get()
+	if err != nil {
+		ready <- errors.Wrap(err, "failed to get net NS handle")
+		return
+	}
+	defer func() { _ = origin.Close() }()
+
+	if err = netns.Set(e.target); err != nil {
+		ready <- errors.Wrapf(err, "failed to switch to the target net NS: %v", e.target)
+		return
+	}
+	ready <- nil
+
+	for {
+		select {
+		case <-e.done:
+			// the main thread is not terminated but parked by the Go runtime, so it must not keep the target
+			// net NS in use
+			_ = netns.Set(origin)
+			return
+		case t := <-e.tasks:
+			result := run(t.runner)
+			if e.isInTarget() {
+				t.result <- result
+				continue
+			}
+			// the runner has left the target net NS and it can't be restored, the thread is dropped and replaced
+			// with a new one. The runner result is returned as is, a failed replacement only shrinks the pool.
+			_ = e.replace()
+			t.result <- result
+			return
+		}
+	}
+}
+
+// replace starts a new worker instead of the calling one, the pool shrinks if the new worker fails to start
+func (e *Executor) replace() error {
+	ready := make(chan error, 1)
+	e.wg.Add(1)
+	go e.work(ready)
+	err := <-ready
+	if err == nil {
+		return nil
+	}
+	err = errors.Wrap(err, "failed to replace the executor worker")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.workers--
+	e.err = err
+	if e.workers == 0 {
+		close(e.gone)
+	}
+	return err
+}
+
+// isInTarget checks that the worker thread is still in the target net NS and tries to switch back otherwise
+func (e *Executor) isInTarget() bool {
+	curr, err := netns.Get()
+	if err != nil {
+		return false
+	}
+	defer func() { _ = curr.Close() }()
+
+	if curr.Equal(e.target) {
+		return true
+	}
+	return netns.Set(e.target) == nil
+}
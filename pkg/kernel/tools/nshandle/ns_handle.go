@@ -22,9 +22,12 @@ package nshandle
 import (
 	"net/url"
 	"runtime"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
 )
 
 // Current creates net NS handle for the current net NS
@@ -37,30 +40,131 @@ func Current() (handle netns.NsHandle, err error) {
 		return -1, errors.Wrap(err, "failed to get net NS handle")
 	}
 
-	return nsHandle, nil
+	return track(nsHandle), nil
 }
 
-// FromURL creates net NS handle by file://path URL
+// FromURL creates net NS handle by URL. Supported schemes are:
+//   - file://<path> - the net NS file path
+//   - pid://<pid> - the net NS of the process, /proc/<pid>/ns/net
+//   - name://<name> - the named net NS, /run/netns/<name>
+//   - inode://<dev>/<inode> - the net NS with the device and the inode, see FromInode
+//   - fd://<fd> - the net NS file descriptor of the process, it is duplicated
 func FromURL(urlString string) (handle netns.NsHandle, err error) {
 	var netNSURL *url.URL
 	netNSURL, err = url.Parse(urlString)
-	if err != nil || netNSURL.Scheme != "file" {
+	if err != nil {
 		return -1, errors.Wrapf(err, "invalid url: %v", urlString)
 	}
 
-	handle, err = netns.GetFromPath(netNSURL.Path)
+	switch netNSURL.Scheme {
+	case "file":
+		handle, err = netns.GetFromPath(netNSURL.Path)
+	case "pid":
+		var pid int
+		if pid, err = strconv.Atoi(urlHost(netNSURL)); err != nil {
+			return -1, errors.Wrapf(err, "invalid pid in url: %v", urlString)
+		}
+		handle, err = netns.GetFromPid(pid)
+	case "name":
+		handle, err = netns.GetFromName(urlHost(netNSURL))
+	case "inode":
+		var dev, inode uint64
+		if dev, inode, err = urlDevInode(netNSURL); err != nil {
+			return -1, err
+		}
+		return fromDevInode(dev, inode)
+	case "fd":
+		var fd int
+		if fd, err = strconv.Atoi(urlHost(netNSURL)); err != nil {
+			return -1, errors.Wrapf(err, "invalid fd in url: %v", urlString)
+		}
+		handle, err = dup(fd)
+	default:
+		return -1, errors.Errorf("invalid url: %v: unknown scheme %q", urlString, netNSURL.Scheme)
+	}
 	if err != nil {
 		return -1, errors.Wrapf(err, "failed to obtain network NS handle")
 	}
 
-	return handle, nil
+	return track(handle), nil
 }
 
-// RunIn runs runner in the given net NS
+// urlDevInode returns the device and the inode of the inode://<dev>/<inode> URL
+func urlDevInode(u *url.URL) (dev, inode uint64, err error) {
+	if dev, err = strconv.ParseUint(u.Host, 10, 64); err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid device in url: %v", u)
+	}
+	if inode, err = strconv.ParseUint(strings.TrimPrefix(u.Path, "/"), 10, 64); err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid inode in url: %v", u)
+	}
+	return dev, inode, nil
+}
+
+// urlHost returns the value of the scheme://<value> URL, the value may be parsed either as host or as opaque
+func urlHost(u *url.URL) string {
+	if u.Host != "" {
+		return u.Host
+	}
+	if u.Opaque != "" {
+		return u.Opaque
+	}
+	return strings.TrimPrefix(u.Path, "/")
+}
+
+func dup(fd int) (netns.NsHandle, error) {
+	newFd, err := unix.Dup(fd)
+	if err != nil {
+		return -1, err
+	}
+	return netns.NsHandle(newFd), nil
+}
+
+// RunIn runs runner in the given net NS. If the target net NS is the current one, the runner is run on the caller's
+// goroutine. Otherwise it is run on a dedicated locked OS thread, if the thread can't be switched back to the current
+// net NS it is terminated instead of being returned to the Go runtime. A runner panic is re-raised on the caller's
+// goroutine.
 func RunIn(current, target netns.NsHandle, runner func() error) error {
-	runtime.LockOSThread()
-	defer runtime.UnlockOSThread()
+	if target.Equal(current) {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
 
+		if err := checkCurrent(current); err != nil {
+			return err
+		}
+		return runner()
+	}
+
+	results := make(chan *runResult, 1)
+	go func() {
+		runtime.LockOSThread()
+
+		if err := checkCurrent(current); err != nil {
+			runtime.UnlockOSThread()
+			results <- &runResult{err: err}
+			return
+		}
+
+		if err := netns.Set(target); err != nil {
+			// the thread net NS is unknown, so it is left locked to be terminated
+			results <- &runResult{err: errors.Wrapf(err, "failed to switch to the target net NS: %v", target)}
+			return
+		}
+		result := run(runner)
+		if err := netns.Set(current); err != nil {
+			// the thread is left locked to be terminated
+			if !result.panicked {
+				result.err = errors.Wrapf(err, "failed to switch back to the current net NS: %v", current)
+			}
+			results <- result
+			return
+		}
+		runtime.UnlockOSThread()
+		results <- result
+	}()
+	return (<-results).get()
+}
+
+func checkCurrent(current netns.NsHandle) error {
 	curr, err := netns.Get()
 	if err != nil {
 		return errors.Wrap(err, "failed to get net NS handle")
@@ -70,17 +174,32 @@ func RunIn(current, target netns.NsHandle, runner func() error) error {
 	if !curr.Equal(current) {
 		return errors.Errorf("current net NS is not the given current net NS: %v != %v", curr, current)
 	}
+	return nil
+}
+
+// runResult is the result of a runner run on another goroutine, the runner panic is kept to be re-raised
+type runResult struct {
+	err        error
+	panicked   bool
+	panicValue interface{}
+}
 
-	if !target.Equal(current) {
-		if err = netns.Set(target); err != nil {
-			return errors.Wrapf(err, "failed to switch to the target net NS: %v", target)
+func run(runner func() error) (result *runResult) {
+	result = &runResult{panicked: true}
+	defer func() {
+		if result.panicked {
+			result.panicValue = recover()
 		}
-		defer func() {
-			if err := netns.Set(current); err != nil {
-				panic(errors.Wrapf(err, "failed to switch back to the current net NS: %v", current).Error())
-			}
-		}()
-	}
+	}()
+	result.err = runner()
+	result.panicked = false
+	return result
+}
 
-	return runner()
+// get returns the runner error or re-raises the runner panic
+func (r *runResult) get() error {
+	if r.panicked {
+		panic(r.panicValue)
+	}
+	return r.err
 }
@@ -0,0 +1,156 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package nshandle
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// netNSBindMountPath is the directory of the named net NSes used by netns.GetFromName
+const netNSBindMountPath = "/run/netns"
+
+// netNSPathPatterns are the locations searched for a net NS with the given inode
+var netNSPathPatterns = []string{
+	"/var/run/netns/*",
+	"/proc/[0-9]*/ns/net",
+}
+
+// FromInode creates net NS handle for the net NS with the given inode. It searches the named net NSes and the
+// net NSes of the visible processes only, so a failure does not mean the net NS is gone: it may be bind mounted
+// elsewhere or used by processes of another PID NS.
+func FromInode(inode uint64) (handle netns.NsHandle, err error) {
+	return fromStat(func(s *unix.Stat_t) bool { return s.Ino == inode },
+		errors.Errorf("failed to find network NS with inode %d", inode))
+}
+
+// fromDevInode creates net NS handle for the net NS with the given device and inode, see FromInode
+func fromDevInode(dev, inode uint64) (handle netns.NsHandle, err error) {
+	return fromStat(func(s *unix.Stat_t) bool { return s.Dev == dev && s.Ino == inode },
+		errors.Errorf("failed to find network NS with device %d and inode %d", dev, inode))
+}
+
+// fromStat opens the first net NS found whose stat matches, the opened handle is checked again since the path may
+// have been reused meanwhile
+func fromStat(matches func(s *unix.Stat_t) bool, notFound error) (netns.NsHandle, error) {
+	for _, pattern := range netNSPathPatterns {
+		paths, _ := filepath.Glob(pattern)
+		for _, path := range paths {
+			var s unix.Stat_t
+			if unix.Stat(path, &s) != nil || !matches(&s) {
+				continue
+			}
+			handle, err := netns.GetFromPath(path)
+			if err != nil {
+				continue
+			}
+			if unix.Fstat(int(handle), &s) != nil || !matches(&s) {
+				_ = handle.Close()
+				continue
+			}
+			return track(handle), nil
+		}
+	}
+	return -1, notFound
+}
+
+// Referenced reports whether the net NS with the given inode is used by a thread or bind mounted in the mount NS of
+// the process. A net NS which is not referenced is kept only by the open handles and is destroyed once they are
+// closed. The threads which can't be inspected are not considered, if nothing can be checked the net NS is reported
+// as referenced.
+func Referenced(inode uint64) bool {
+	paths, err := filepath.Glob("/proc/[0-9]*/task/[0-9]*/ns/net")
+	if err != nil || len(paths) == 0 {
+		return true
+	}
+	for _, path := range paths {
+		var s unix.Stat_t
+		if unix.Stat(path, &s) == nil && s.Ino == inode {
+			return true
+		}
+	}
+
+	// the bind mounts of a net NS have "net:[<inode>]" as the root in the mountinfo
+	mountInfo, err := os.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return true
+	}
+	root := fmt.Sprintf("net:[%d]", inode)
+	for _, line := range strings.Split(string(mountInfo), "\n") {
+		if fields := strings.Fields(line); len(fields) > 3 && fields[3] == root {
+			return true
+		}
+	}
+	return false
+}
+
+// StatURL returns the device and the inode of the net NS specified by the URL without opening the net NS, see FromURL
+// for the supported schemes
+func StatURL(urlString string) (dev, inode uint64, err error) {
+	netNSURL, err := url.Parse(urlString)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid url: %v", urlString)
+	}
+
+	var s unix.Stat_t
+	switch netNSURL.Scheme {
+	case "file":
+		err = unix.Stat(netNSURL.Path, &s)
+	case "pid":
+		var pid int
+		if pid, err = strconv.Atoi(urlHost(netNSURL)); err != nil {
+			return 0, 0, errors.Wrapf(err, "invalid pid in url: %v", urlString)
+		}
+		err = unix.Stat(fmt.Sprintf("/proc/%d/ns/net", pid), &s)
+	case "name":
+		err = unix.Stat(filepath.Join(netNSBindMountPath, urlHost(netNSURL)), &s)
+	case "inode":
+		return urlDevInode(netNSURL)
+	case "fd":
+		var fd int
+		if fd, err = strconv.Atoi(urlHost(netNSURL)); err != nil {
+			return 0, 0, errors.Wrapf(err, "invalid fd in url: %v", urlString)
+		}
+		err = unix.Fstat(fd, &s)
+	default:
+		return 0, 0, errors.Errorf("invalid url: %v: unknown scheme %q", urlString, netNSURL.Scheme)
+	}
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to stat network NS: %v", urlString)
+	}
+	return s.Dev, s.Ino, nil
+}
+
+// Inode returns the inode of the given net NS
+func Inode(handle netns.NsHandle) (uint64, error) {
+	var s unix.Stat_t
+	if err := unix.Fstat(int(handle), &s); err != nil {
+		return 0, errors.Wrapf(err, "failed to stat network NS handle %v", handle)
+	}
+	return s.Ino, nil
+}
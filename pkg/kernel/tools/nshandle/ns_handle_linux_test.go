@@ -20,6 +20,9 @@
 package nshandle_test
 
 import (
+	"context"
+	"fmt"
+	"os"
 	"runtime"
 	"sync"
 	"testing"
@@ -27,6 +30,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vishvananda/netns"
 	"go.uber.org/goleak"
+	"golang.org/x/sys/unix"
 
 	"github.com/ljkiraly/sdk-kernel/pkg/kernel/tools/nshandle"
 )
@@ -74,6 +78,242 @@ func TestNSHandle_RunInPerm(t *testing.T) {
 	wg.Wait()
 }
 
+func TestNSHandle_ExecutorPerm(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t) })
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	executor, err := nshandle.NewExecutor(target, 4)
+	require.NoError(t, err)
+	defer func() { _ = executor.Close() }()
+
+	wg := sync.WaitGroup{}
+	wg.Add(concurrentCount)
+
+	for i := 0; i < concurrentCount; i++ {
+		go func() {
+			defer wg.Done()
+
+			err := executor.Run(context.Background(), func() error {
+				handle, err := netns.Get()
+				require.NoError(t, err)
+				defer func() { _ = handle.Close() }()
+
+				require.True(t, target.Equal(handle), equalFormat, target, handle)
+
+				return nil
+			})
+			require.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestNSHandle_RunInNoLeaksPerm(t *testing.T) {
+	nshandle.VerifyNoLeaks(t)
+
+	current, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = current.Close() }()
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	for _, ns := range []netns.NsHandle{current, target} {
+		err = nshandle.RunIn(current, ns, func() error {
+			handle, err := nshandle.Current()
+			if err != nil {
+				return err
+			}
+			defer func() { _ = handle.Close() }()
+
+			require.True(t, ns.Equal(handle), equalFormat, ns, handle)
+			return nil
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestNSHandle_RunInPanicPerm(t *testing.T) {
+	current, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = current.Close() }()
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	for _, ns := range []netns.NsHandle{current, target} {
+		require.PanicsWithValue(t, "runner panic", func() {
+			_ = nshandle.RunIn(current, ns, func() error {
+				panic("runner panic")
+			})
+		})
+	}
+
+	executor, err := nshandle.NewExecutor(target, 1)
+	require.NoError(t, err)
+	defer func() { _ = executor.Close() }()
+
+	require.PanicsWithValue(t, "runner panic", func() {
+		_ = executor.Run(context.Background(), func() error {
+			panic("runner panic")
+		})
+	})
+	require.NoError(t, executor.Run(context.Background(), func() error { return nil }))
+}
+
+func TestNSHandle_FromURLPerm(t *testing.T) {
+	const name = "nsm-from-url"
+	target := newNamedNSHandle(t, name)
+	defer func() {
+		_ = target.Close()
+		_ = netns.DeleteNamed(name)
+	}()
+
+	// the main thread may be parked in another net NS by the other tests
+	mainThreadNetNS, err := netns.GetFromPath(fmt.Sprintf("/proc/%d/ns/net", os.Getpid()))
+	require.NoError(t, err)
+	defer func() { _ = mainThreadNetNS.Close() }()
+
+	var s unix.Stat_t
+	require.NoError(t, unix.Fstat(int(target), &s))
+
+	samples := []struct {
+		Name     string
+		URL      string
+		Expected netns.NsHandle
+	}{
+		{Name: "file", URL: "file:///var/run/netns/" + name, Expected: target},
+		{Name: "pid", URL: fmt.Sprintf("pid://%d", os.Getpid()), Expected: mainThreadNetNS},
+		{Name: "name", URL: "name://" + name, Expected: target},
+		{Name: "inode", URL: fmt.Sprintf("inode://%d/%d", s.Dev, s.Ino), Expected: target},
+		{Name: "fd", URL: fmt.Sprintf("fd://%d", int(target)), Expected: target},
+		{Name: "inode with wrong device", URL: fmt.Sprintf("inode://%d/%d", s.Dev+1, s.Ino)},
+		{Name: "inode without device", URL: fmt.Sprintf("inode://%d", s.Ino)},
+		{Name: "invalid pid", URL: "pid://nsm"},
+		{Name: "unknown name", URL: "name://nsm-unknown"},
+		{Name: "invalid fd", URL: "fd://-1"},
+		{Name: "unknown scheme", URL: "nsm://" + name},
+	}
+	for _, sample := range samples {
+		t.Run(sample.Name, func(t *testing.T) {
+			handle, err := nshandle.FromURL(sample.URL)
+			if sample.Expected == 0 {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer func() { _ = handle.Close() }()
+			require.True(t, sample.Expected.Equal(handle), equalFormat, sample.Expected, handle)
+		})
+	}
+}
+
+func TestNSHandle_ReferencedPerm(t *testing.T) {
+	const name = "nsm-referenced"
+	target := newNamedNSHandle(t, name)
+	defer func() {
+		_ = target.Close()
+		_ = netns.DeleteNamed(name)
+	}()
+
+	current, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = current.Close() }()
+
+	inode, err := nshandle.Inode(target)
+	require.NoError(t, err)
+
+	// bind mounted
+	require.True(t, nshandle.Referenced(inode))
+
+	// kept by the handle only
+	require.NoError(t, netns.DeleteNamed(name))
+	require.False(t, nshandle.Referenced(inode))
+
+	// used by a thread
+	err = nshandle.RunIn(current, target, func() error {
+		require.True(t, nshandle.Referenced(inode))
+		return nil
+	})
+	require.NoError(t, err)
+	require.False(t, nshandle.Referenced(inode))
+}
+
+type fakeT struct {
+	cleanups []func()
+	errors   []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) finish() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func TestNSHandle_VerifyNoLeaksPerm(t *testing.T) {
+	ft := new(fakeT)
+	nshandle.VerifyNoLeaks(ft)
+
+	closed, err := nshandle.Current()
+	require.NoError(t, err)
+	require.NoError(t, closed.Close())
+
+	leaked, err := nshandle.FromURL(fmt.Sprintf("pid://%d", os.Getpid()))
+	require.NoError(t, err)
+	defer func() { _ = leaked.Close() }()
+
+	ft.finish()
+
+	require.Len(t, ft.errors, 1)
+	require.Contains(t, ft.errors[0], fmt.Sprintf("net NS handle %v", leaked))
+	require.Contains(t, ft.errors[0], "TestNSHandle_VerifyNoLeaksPerm")
+}
+
+func TestNSHandle_LeaksPerm(t *testing.T) {
+	nshandle.EnableTracking()
+	defer nshandle.DisableTracking()
+
+	handle, err := nshandle.Current()
+	require.NoError(t, err)
+
+	leaks := nshandle.Leaks()
+	require.Len(t, leaks, 1)
+	require.Equal(t, handle, leaks[0].Handle)
+
+	require.NoError(t, handle.Close())
+	require.Empty(t, nshandle.Leaks())
+}
+
+func newNamedNSHandle(t *testing.T, name string) netns.NsHandle {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	baseHandle, err := netns.Get()
+	require.NoError(t, err)
+	defer func() {
+		_ = netns.Set(baseHandle)
+		_ = baseHandle.Close()
+	}()
+
+	newHandle, err := netns.NewNamed(name)
+	require.NoError(t, err)
+
+	return newHandle
+}
+
 func newNSHandle(t *testing.T) netns.NsHandle {
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
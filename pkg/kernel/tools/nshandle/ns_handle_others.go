@@ -0,0 +1,49 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package nshandle
+
+import (
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netns"
+)
+
+// FromInode is not supported on this platform
+func FromInode(inode uint64) (handle netns.NsHandle, err error) {
+	return -1, errors.Errorf("failed to find network NS with inode %d: not supported", inode)
+}
+
+func fromDevInode(dev, inode uint64) (handle netns.NsHandle, err error) {
+	return -1, errors.Errorf("failed to find network NS with device %d and inode %d: not supported", dev, inode)
+}
+
+// Referenced is not supported on this platform, the net NS is reported as referenced
+func Referenced(inode uint64) bool {
+	return true
+}
+
+// StatURL is not supported on this platform
+func StatURL(urlString string) (dev, inode uint64, err error) {
+	return 0, 0, errors.Errorf("failed to stat network NS %v: not supported", urlString)
+}
+
+// Inode is not supported on this platform
+func Inode(handle netns.NsHandle) (uint64, error) {
+	return 0, errors.Errorf("failed to stat network NS handle %v: not supported", handle)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2026 Nordix Foundation.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nshandle
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+const maxStackDepth = 32
+
+// Leak is a net NS handle created by the package which is still open
+type Leak struct {
+	Handle netns.NsHandle
+	Opened time.Time
+	Stack  string
+
+	seq uint64
+}
+
+func (l *Leak) String() string {
+	return fmt.Sprintf("net NS handle %v opened at %s and not closed:\n%s", l.Handle, l.Opened.Format(time.RFC3339Nano), l.Stack)
+}
+
+type trackedHandle struct {
+	stat unix.Stat_t
+	leak *Leak
+}
+
+var tracker = struct {
+	sync.Mutex
+	enabled  bool
+	verifies int
+	seq      uint64
+	handles  map[netns.NsHandle]*trackedHandle
+}{
+	handles: make(map[netns.NsHandle]*trackedHandle),
+}
+
+// EnableTracking makes the package record where every net NS handle returned by Current, FromURL and FromInode
+// has been created, so the handles which are not closed can be reported with Leaks
+func EnableTracking() {
+	tracker.Lock()
+	defer tracker.Unlock()
+
+	tracker.enabled = true
+}
+
+// DisableTracking stops recording the created net NS handles and forgets the recorded ones
+func DisableTracking() {
+	tracker.Lock()
+	defer tracker.Unlock()
+
+	tracker.enabled = false
+	if tracker.verifies == 0 {
+		tracker.handles = make(map[netns.NsHandle]*trackedHandle)
+	}
+}
+
+// Leaks returns the recorded net NS handles which are still open ordered by creation time. A handle is considered
+// closed once its fd is closed or reused for another file.
+func Leaks() []*Leak {
+	tracker.Lock()
+	defer tracker.Unlock()
+
+	return leaks(0)
+}
+
+func leaks(fromSeq uint64) []*Leak {
+	var result []*Leak
+	for handle, tracked := range tracker.handles {
+		var s unix.Stat_t
+		if err := unix.Fstat(int(handle), &s); err != nil || s.Dev != tracked.stat.Dev || s.Ino != tracked.stat.Ino {
+			delete(tracker.handles, handle)
+			continue
+		}
+		if tracked.leak.seq >= fromSeq {
+			result = append(result, tracked.leak)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].seq < result[j].seq
+	})
+	return result
+}
+
+// track records the handle if tracking is enabled
+func track(handle netns.NsHandle) netns.NsHandle {
+	tracker.Lock()
+	defer tracker.Unlock()
+
+	if !tracker.enabled && tracker.verifies == 0 {
+		return handle
+	}
+
+	var s unix.Stat_t
+	if err := unix.Fstat(int(handle), &s); err != nil {
+		return handle
+	}
+
+	tracker.seq++
+	tracker.handles[handle] = &trackedHandle{
+		stat: s,
+		leak: &Leak{
+			Handle: handle,
+			Opened: time.Now(),
+			Stack:  stack(),
+			seq:    tracker.seq,
+		},
+	}
+	return handle
+}
+
+func stack() string {
+	pcs := make([]uintptr, maxStackDepth)
+	// skip runtime.Callers, stack and track
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+
+	var sb strings.Builder
+	for {
+		frame, more := frames.Next()
+		_, _ = fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// TestingT is the subset of testing.TB used by VerifyNoLeaks
+type TestingT interface {
+	Helper()
+	Cleanup(func())
+	Errorf(format string, args ...interface{})
+}
+
+// VerifyNoLeaks records the net NS handles created from now on until the end of the test and fails the test if
+// some of them are still open on the test cleanup, similar to goleak for goroutines:
+//
+//	func TestSomething(t *testing.T) {
+//		nshandle.VerifyNoLeaks(t)
+//		...
+//	}
+func VerifyNoLeaks(t TestingT) {
+	t.Helper()
+
+	tracker.Lock()
+	tracker.verifies++
+	fromSeq := tracker.seq + 1
+	tracker.Unlock()
+
+	t.Cleanup(func() {
+		t.Helper()
+
+		tracker.Lock()
+		found := leaks(fromSeq)
+		tracker.verifies--
+		if !tracker.enabled && tracker.verifies == 0 {
+			tracker.handles = make(map[netns.NsHandle]*trackedHandle)
+		}
+		tracker.Unlock()
+
+		for _, l := range found {
+			t.Errorf("found unexpected %s", l.String())
+		}
+	})
+}